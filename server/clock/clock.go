@@ -0,0 +1,51 @@
+// Package clock abstracts the current time so that time-dependent logic
+// such as interest accrual, daily limits and cache expiry can be driven
+// deterministically.
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock tells the current time.
+type Clock interface {
+	Now() time.Time
+}
+
+// Real is the system clock.
+type Real struct{}
+
+func (Real) Now() time.Time { return time.Now() }
+
+// Mock is a Clock that only moves when told to. It is safe for concurrent
+// use.
+type Mock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewMock returns a Mock stopped at now.
+func NewMock(now time.Time) *Mock {
+	return &Mock{now: now}
+}
+
+func (m *Mock) Now() time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.now
+}
+
+// Advance moves the clock forward by d.
+func (m *Mock) Advance(d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.now = m.now.Add(d)
+}
+
+// Set moves the clock to now.
+func (m *Mock) Set(now time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.now = now
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Getenv returns the value of the environment variable key, or fallback
+// when it is unset or empty.
+func Getenv(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+// GetDuration parses the environment variable key as a time.Duration
+// ("10s", "1m"), returning fallback when it is unset.
+func GetDuration(key string, fallback time.Duration) (time.Duration, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("%s must be a positive duration such as 10s, got %q", key, v)
+	}
+	return d, nil
+}
+
+// GetInt parses the environment variable key as a positive integer,
+// returning fallback when it is unset.
+func GetInt(key string, fallback int) (int, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("%s must be a positive integer, got %q", key, v)
+	}
+	return n, nil
+}
+
+// GetRate parses the environment variable key as a fraction between 0 and
+// 1 ("0.025" for 2.5%), returning fallback when it is unset.
+func GetRate(key string, fallback *big.Rat) (*big.Rat, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback, nil
+	}
+	r, ok := new(big.Rat).SetString(v)
+	if !ok || !validRate(r) {
+		return nil, fmt.Errorf("%s must be a rate between 0 and 1 such as 0.02, got %q", key, v)
+	}
+	return r, nil
+}
+
+func validRate(r *big.Rat) bool {
+	return r.Sign() >= 0 && r.Cmp(big.NewRat(1, 1)) <= 0
+}
+
+// GetList splits the comma-separated environment variable key, dropping
+// empty items.
+func GetList(key string) []string {
+	var items []string
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
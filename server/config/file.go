@@ -0,0 +1,200 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap/zapcore"
+	"go.yaml.in/yaml/v3"
+)
+
+// DefaultRateLimit is the number of requests per minute allowed for each
+// client when RATE_LIMIT_PER_MINUTE is not set.
+const DefaultRateLimit = 120
+
+// Config is the server configuration. It is read from the YAML or JSON
+// file named by CONFIG_PATH, if any, and then each environment variable
+// noted beside a field overrides the file's value.
+type Config struct {
+	Host               string        `yaml:"host"`                  // HOST
+	Port               int           `yaml:"port"`                  // PORT
+	DBDSN              string        `yaml:"db_dsn"`                // DB_DSN
+	JWTSecret          string        `yaml:"jwt_secret"`            // JWT_SECRET, required
+	LogLevel           string        `yaml:"log_level"`             // LOG_LEVEL
+	RateLimitPerMinute int           `yaml:"rate_limit_per_minute"` // RATE_LIMIT_PER_MINUTE
+	ShutdownTimeout    time.Duration `yaml:"shutdown_timeout"`      // SHUTDOWN_TIMEOUT
+	RequestTimeout     time.Duration `yaml:"request_timeout"`       // REQUEST_TIMEOUT
+	SchedulerInterval  time.Duration `yaml:"scheduler_interval"`    // SCHEDULER_INTERVAL
+	InterestRate       *big.Rat      `yaml:"interest_rate"`         // INTEREST_RATE
+	FXRates            string        `yaml:"fx_rates"`              // FX_RATES
+	AdminEmails        []string      `yaml:"admin_emails"`          // ADMIN_EMAILS
+	AllowedOrigins     []string      `yaml:"allowed_origins"`       // ALLOWED_ORIGINS
+	RedisURL           string        `yaml:"redis_url"`             // REDIS_URL
+	PublicURL          string        `yaml:"public_url"`            // PUBLIC_URL
+	SMTPAddr           string        `yaml:"smtp_addr"`             // SMTP_ADDR
+	SMTPUsername       string        `yaml:"smtp_username"`         // SMTP_USERNAME
+	SMTPPassword       string        `yaml:"smtp_password"`         // SMTP_PASSWORD
+	MailFrom           string        `yaml:"mail_from"`             // MAIL_FROM
+}
+
+// Default returns the configuration used for everything neither the file
+// nor the environment sets.
+func Default() Config {
+	return Config{
+		Port:               1323,
+		DBDSN:              "banksystem.db",
+		LogLevel:           "info",
+		RateLimitPerMinute: DefaultRateLimit,
+		ShutdownTimeout:    10 * time.Second,
+		RequestTimeout:     30 * time.Second,
+		SchedulerInterval:  time.Minute,
+		InterestRate:       big.NewRat(2, 100),
+		PublicURL:          "http://localhost:1323",
+	}
+}
+
+// Load builds the configuration from the defaults, the file at path (skipped
+// when path is empty) and the environment, in increasing precedence, and
+// validates the result.
+func Load(path string) (Config, error) {
+	cfg := Default()
+	if path != "" {
+		if err := cfg.readFile(path); err != nil {
+			return Config{}, err
+		}
+	}
+	if err := cfg.applyEnv(); err != nil {
+		return Config{}, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+func (c *Config) readFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("read config file: %w", err)
+	}
+	defer f.Close()
+
+	// JSON is valid YAML, so one decoder handles both formats. Unknown keys
+	// are rejected so a misspelt setting doesn't silently fall back.
+	dec := yaml.NewDecoder(f)
+	dec.KnownFields(true)
+	if err := dec.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("parse config file %s: %w", path, err)
+	}
+	return nil
+}
+
+func (c *Config) applyEnv() error {
+	var errs []error
+	c.Host = Getenv("HOST", c.Host)
+	if v := os.Getenv("PORT"); v != "" {
+		port, err := strconv.Atoi(v)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("PORT must be a number between 0 and 65535, got %q", v))
+		}
+		c.Port = port
+	}
+	c.DBDSN = Getenv("DB_DSN", c.DBDSN)
+	c.JWTSecret = Getenv("JWT_SECRET", c.JWTSecret)
+	c.LogLevel = Getenv("LOG_LEVEL", c.LogLevel)
+
+	var err error
+	if c.RateLimitPerMinute, err = GetInt("RATE_LIMIT_PER_MINUTE", c.RateLimitPerMinute); err != nil {
+		errs = append(errs, err)
+	}
+	if c.ShutdownTimeout, err = GetDuration("SHUTDOWN_TIMEOUT", c.ShutdownTimeout); err != nil {
+		errs = append(errs, err)
+	}
+	if c.RequestTimeout, err = GetDuration("REQUEST_TIMEOUT", c.RequestTimeout); err != nil {
+		errs = append(errs, err)
+	}
+	if c.SchedulerInterval, err = GetDuration("SCHEDULER_INTERVAL", c.SchedulerInterval); err != nil {
+		errs = append(errs, err)
+	}
+	if c.InterestRate, err = GetRate("INTEREST_RATE", c.InterestRate); err != nil {
+		errs = append(errs, err)
+	}
+
+	c.FXRates = Getenv("FX_RATES", c.FXRates)
+	if v := GetList("ADMIN_EMAILS"); v != nil {
+		c.AdminEmails = v
+	}
+	if v := GetList("ALLOWED_ORIGINS"); v != nil {
+		c.AllowedOrigins = v
+	}
+	c.RedisURL = Getenv("REDIS_URL", c.RedisURL)
+	c.PublicURL = Getenv("PUBLIC_URL", c.PublicURL)
+	c.SMTPAddr = Getenv("SMTP_ADDR", c.SMTPAddr)
+	c.SMTPUsername = Getenv("SMTP_USERNAME", c.SMTPUsername)
+	c.SMTPPassword = Getenv("SMTP_PASSWORD", c.SMTPPassword)
+	c.MailFrom = Getenv("MAIL_FROM", c.MailFrom)
+	return errors.Join(errs...)
+}
+
+// Validate reports every missing or out-of-range setting at once, naming
+// both the file key and the environment variable.
+func (c Config) Validate() error {
+	var errs []error
+	if c.JWTSecret == "" {
+		errs = append(errs, errors.New("jwt_secret (JWT_SECRET) is required"))
+	}
+	if c.Port < 0 || c.Port > 65535 {
+		errs = append(errs, fmt.Errorf("port (PORT) must be between 0 and 65535, got %d", c.Port))
+	}
+	if c.DBDSN == "" {
+		errs = append(errs, errors.New("db_dsn (DB_DSN) is required"))
+	}
+	if _, err := zapcore.ParseLevel(c.LogLevel); err != nil {
+		errs = append(errs, fmt.Errorf("log_level (LOG_LEVEL) must be one of debug, info, warn or error, got %q", c.LogLevel))
+	}
+	if c.RateLimitPerMinute <= 0 {
+		errs = append(errs, fmt.Errorf("rate_limit_per_minute (RATE_LIMIT_PER_MINUTE) must be positive, got %d", c.RateLimitPerMinute))
+	}
+	if c.ShutdownTimeout <= 0 {
+		errs = append(errs, fmt.Errorf("shutdown_timeout (SHUTDOWN_TIMEOUT) must be positive, got %s", c.ShutdownTimeout))
+	}
+	if c.RequestTimeout <= 0 {
+		errs = append(errs, fmt.Errorf("request_timeout (REQUEST_TIMEOUT) must be positive, got %s", c.RequestTimeout))
+	}
+	if c.SchedulerInterval <= 0 {
+		errs = append(errs, fmt.Errorf("scheduler_interval (SCHEDULER_INTERVAL) must be positive, got %s", c.SchedulerInterval))
+	}
+	if c.InterestRate == nil || !validRate(c.InterestRate) {
+		errs = append(errs, errors.New("interest_rate (INTEREST_RATE) must be between 0 and 1"))
+	}
+	if c.RedisURL != "" {
+		if _, err := redis.ParseURL(c.RedisURL); err != nil {
+			errs = append(errs, fmt.Errorf("redis_url (REDIS_URL) must be a redis:// URL: %w", err))
+		}
+	}
+	if u, err := url.Parse(c.PublicURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		errs = append(errs, fmt.Errorf("public_url (PUBLIC_URL) must be an absolute http(s) URL, got %q", c.PublicURL))
+	}
+	if c.SMTPAddr != "" {
+		if _, _, err := net.SplitHostPort(c.SMTPAddr); err != nil {
+			errs = append(errs, fmt.Errorf("smtp_addr (SMTP_ADDR) must be a host:port, got %q", c.SMTPAddr))
+		}
+		if c.MailFrom == "" {
+			errs = append(errs, errors.New("mail_from (MAIL_FROM) is required when smtp_addr is set"))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Address is the host:port the server listens on.
+func (c Config) Address() string {
+	return net.JoinHostPort(c.Host, strconv.Itoa(c.Port))
+}
@@ -0,0 +1,109 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"BankSystemGoLang/config"
+)
+
+func TestAddress(t *testing.T) {
+	tests := []struct {
+		host string
+		port int
+		want string
+	}{
+		{host: "", port: 1323, want: ":1323"},
+		{host: "127.0.0.1", port: 8080, want: "127.0.0.1:8080"},
+		{host: "localhost", port: 0, want: "localhost:0"},
+		{host: "::1", port: 443, want: "[::1]:443"},
+	}
+	for _, tt := range tests {
+		cfg := config.Config{Host: tt.host, Port: tt.port}
+		if got := cfg.Address(); got != tt.want {
+			t.Errorf("Address() with host %q port %d = %q, want %q", tt.host, tt.port, got, tt.want)
+		}
+	}
+}
+
+func TestLoadPort(t *testing.T) {
+	tests := []struct {
+		port    string
+		want    int
+		wantErr string
+	}{
+		{port: "", want: 1323},
+		{port: "8080", want: 8080},
+		{port: "0", want: 0},
+		{port: "65535", want: 65535},
+		{port: "65536", wantErr: "port (PORT) must be between 0 and 65535"},
+		{port: "-1", wantErr: "port (PORT) must be between 0 and 65535"},
+		{port: "http", wantErr: "PORT must be a number"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.port, func(t *testing.T) {
+			t.Setenv("JWT_SECRET", "secret")
+			t.Setenv("PORT", tt.port)
+
+			cfg, err := config.Load("")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cfg.Port != tt.want {
+				t.Errorf("port = %d, want %d", cfg.Port, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadEnvOverridesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	file := "port: 8080\ndb_dsn: from-file.db\njwt_secret: file-secret\nlog_level: debug\nadmin_emails: [file@example.com]\n"
+	if err := os.WriteFile(path, []byte(file), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PORT", "")
+	t.Setenv("LOG_LEVEL", "")
+	t.Setenv("DB_DSN", "from-env.db")
+	t.Setenv("JWT_SECRET", "env-secret")
+	t.Setenv("ADMIN_EMAILS", "env@example.com, ops@example.com")
+
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Port != 8080 || cfg.LogLevel != "debug" {
+		t.Errorf("port %d, log level %q, want the file's 8080 and debug", cfg.Port, cfg.LogLevel)
+	}
+	if cfg.DBDSN != "from-env.db" || cfg.JWTSecret != "env-secret" {
+		t.Errorf("db_dsn %q, jwt_secret %q, want the environment's values", cfg.DBDSN, cfg.JWTSecret)
+	}
+	if !slices.Equal(cfg.AdminEmails, []string{"env@example.com", "ops@example.com"}) {
+		t.Errorf("admin emails = %v, want the environment's list", cfg.AdminEmails)
+	}
+}
+
+func TestLoadRequiresJWTSecret(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("port: 8080\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("JWT_SECRET", "")
+	t.Setenv("PORT", "")
+
+	for _, path := range []string{"", path} {
+		_, err := config.Load(path)
+		if err == nil || !strings.Contains(err.Error(), "jwt_secret (JWT_SECRET) is required") {
+			t.Errorf("Load(%q): err = %v, want the missing JWT secret reported", path, err)
+		}
+	}
+}
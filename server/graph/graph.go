@@ -0,0 +1,98 @@
+// Package graph serves the GraphQL API. Its resolvers call the same
+// services as the REST handlers and apply the same ownership checks, so the
+// two APIs cannot drift apart.
+package graph
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+
+	"github.com/graph-gophers/graphql-go"
+	"go.uber.org/zap"
+
+	"BankSystemGoLang/services"
+	"BankSystemGoLang/types"
+)
+
+//go:embed schema.graphql
+var schema string
+
+// NewSchema parses the GraphQL schema and binds it to resolvers backed by
+// accounts. Unexpected errors are logged to log and reported to clients
+// without their details.
+func NewSchema(accounts *services.AccountService, log *zap.Logger) (*graphql.Schema, error) {
+	return graphql.ParseSchema(schema, &resolver{accounts: accounts, log: log})
+}
+
+// Caller identifies who a GraphQL request runs for.
+type Caller struct {
+	UserID    int64
+	RequestID string
+}
+
+type callerKey struct{}
+
+// WithCaller returns a copy of ctx carrying caller, which the resolvers
+// check account ownership against.
+func WithCaller(ctx context.Context, caller Caller) context.Context {
+	return context.WithValue(ctx, callerKey{}, caller)
+}
+
+func callerFrom(ctx context.Context) Caller {
+	caller, _ := ctx.Value(callerKey{}).(Caller)
+	return caller
+}
+
+// Error is a failed field, reported with the same machine-readable code
+// the REST API would return in extensions.code.
+type Error struct {
+	Code    string
+	Message string
+}
+
+func (e *Error) Error() string { return e.Message }
+
+func (e *Error) Extensions() map[string]any {
+	return map[string]any{"code": e.Code}
+}
+
+var errForbidden = &Error{Code: types.CodeForbidden, Message: "you do not have access to this account"}
+
+// fail turns a service error into the *Error reported to the client.
+func (r *resolver) fail(ctx context.Context, err error) error {
+	var limit *services.DailyLimitError
+	switch {
+	case errors.As(err, new(*Error)):
+		return err
+	case errors.As(err, &limit):
+		return &Error{Code: types.CodeDailyLimitExceeded, Message: limit.Error()}
+	case errors.Is(err, context.DeadlineExceeded):
+		return &Error{Code: types.CodeTimeout, Message: "request timed out"}
+	case errors.Is(err, services.ErrExchangeRate):
+		// The provider's error may describe internals; it is not passed on.
+		return &Error{Code: types.CodeExchangeRate, Message: services.ErrExchangeRate.Error()}
+	}
+
+	for _, known := range []struct {
+		err  error
+		code string
+	}{
+		{services.ErrAccountNotFound, types.CodeAccountNotFound},
+		{services.ErrInvalidAmount, types.CodeInvalidAmount},
+		{services.ErrInsufficientFunds, types.CodeInsufficientFunds},
+		{services.ErrSameAccount, types.CodeSameAccount},
+		{services.ErrAccountClosed, types.CodeAccountClosed},
+		{services.ErrAccountFrozen, types.CodeAccountFrozen},
+		{services.ErrConcurrentUpdate, types.CodeConcurrentUpdate},
+		{services.ErrInvalidPagination, types.CodeInvalidPagination},
+		{services.ErrInvalidCursor, types.CodeInvalidCursor},
+	} {
+		if errors.Is(err, known.err) {
+			return &Error{Code: known.code, Message: err.Error()}
+		}
+	}
+
+	r.log.Error("graphql resolver failed", zap.String("request_id", callerFrom(ctx).RequestID), zap.Error(err))
+	return &Error{Code: types.CodeInternal, Message: "internal server error"}
+}
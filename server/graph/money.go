@@ -0,0 +1,39 @@
+package graph
+
+import (
+	"fmt"
+
+	"BankSystemGoLang/types"
+)
+
+// Money is the GraphQL Money scalar: a types.Money written as a decimal
+// string. Input may be such a string or an Int of whole units.
+type Money types.Money
+
+func (Money) ImplementsGraphQLType(name string) bool {
+	return name == "Money"
+}
+
+func (m *Money) UnmarshalGraphQL(input any) error {
+	var (
+		parsed types.Money
+		err    error
+	)
+	switch input := input.(type) {
+	case string:
+		parsed, err = types.ParseMoney(input)
+	case int32:
+		parsed, err = types.ParseMoney(fmt.Sprint(input))
+	default:
+		err = types.ErrInvalidMoney
+	}
+	if err != nil {
+		return err
+	}
+	*m = Money(parsed)
+	return nil
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return types.Money(m).MarshalJSON()
+}
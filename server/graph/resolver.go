@@ -0,0 +1,230 @@
+package graph
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/graph-gophers/graphql-go"
+	"go.uber.org/zap"
+
+	"BankSystemGoLang/services"
+	"BankSystemGoLang/types"
+)
+
+// resolver is the root of both Query and Mutation.
+type resolver struct {
+	accounts *services.AccountService
+	log      *zap.Logger
+}
+
+func (r *resolver) Account(ctx context.Context, args struct{ ID graphql.ID }) (*accountResolver, error) {
+	account, err := r.own(ctx, args.ID)
+	if err != nil {
+		return nil, r.fail(ctx, err)
+	}
+	return &accountResolver{account}, nil
+}
+
+func (r *resolver) Transactions(ctx context.Context, args struct {
+	AccountID graphql.ID
+	Limit     int32
+	Cursor    *string
+}) (*transactionPageResolver, error) {
+	account, err := r.own(ctx, args.AccountID)
+	if err != nil {
+		return nil, r.fail(ctx, err)
+	}
+	var cursor string
+	if args.Cursor != nil {
+		cursor = *args.Cursor
+	}
+	page, err := r.accounts.Transactions(ctx, account.ID, int(args.Limit), 0, cursor)
+	if err != nil {
+		return nil, r.fail(ctx, err)
+	}
+	return &transactionPageResolver{page}, nil
+}
+
+type amountArgs struct {
+	AccountID graphql.ID
+	Amount    Money
+}
+
+func (r *resolver) Deposit(ctx context.Context, args amountArgs) (*accountResolver, error) {
+	account, err := r.own(ctx, args.AccountID)
+	if err != nil {
+		return nil, r.fail(ctx, err)
+	}
+	account, err = r.accounts.Deposit(ctx, account.ID, types.Money(args.Amount), 0)
+	if err != nil {
+		return nil, r.fail(ctx, err)
+	}
+	return &accountResolver{account}, nil
+}
+
+func (r *resolver) Withdraw(ctx context.Context, args amountArgs) (*accountResolver, error) {
+	account, err := r.own(ctx, args.AccountID)
+	if err != nil {
+		return nil, r.fail(ctx, err)
+	}
+	account, err = r.accounts.Withdraw(ctx, account.ID, types.Money(args.Amount), 0)
+	if err != nil {
+		return nil, r.fail(ctx, err)
+	}
+	return &accountResolver{account}, nil
+}
+
+// Transfer needs the caller to own the source account only, as
+// POST /transfers does. The destination account is only shown to its
+// owner.
+func (r *resolver) Transfer(ctx context.Context, args struct {
+	FromID graphql.ID
+	ToID   graphql.ID
+	Amount Money
+}) (*transferResolver, error) {
+	from, err := r.own(ctx, args.FromID)
+	if err != nil {
+		return nil, r.fail(ctx, err)
+	}
+	toID, err := parseID(args.ToID)
+	if err != nil {
+		return nil, r.fail(ctx, err)
+	}
+	from, to, fx, err := r.accounts.Transfer(ctx, from.ID, toID, types.Money(args.Amount))
+	if err != nil {
+		return nil, r.fail(ctx, err)
+	}
+	return &transferResolver{from: from, to: to, ownsTo: to.UserID == from.UserID, amount: types.Money(args.Amount), fx: fx}, nil
+}
+
+// own loads the account with the given ID, failing unless it belongs to
+// the caller.
+func (r *resolver) own(ctx context.Context, gid graphql.ID) (types.Account, error) {
+	id, err := parseID(gid)
+	if err != nil {
+		return types.Account{}, err
+	}
+	account, err := r.accounts.Get(ctx, id)
+	if err != nil {
+		return types.Account{}, err
+	}
+	if account.UserID != callerFrom(ctx).UserID {
+		return types.Account{}, errForbidden
+	}
+	return account, nil
+}
+
+func parseID(gid graphql.ID) (int64, error) {
+	id, err := strconv.ParseInt(string(gid), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, &Error{Code: types.CodeInvalidAccountID, Message: "invalid account id"}
+	}
+	return id, nil
+}
+
+func formatID(id int64) graphql.ID {
+	return graphql.ID(strconv.FormatInt(id, 10))
+}
+
+type accountResolver struct {
+	a types.Account
+}
+
+func (r *accountResolver) ID() graphql.ID              { return formatID(r.a.ID) }
+func (r *accountResolver) UserID() graphql.ID          { return formatID(r.a.UserID) }
+func (r *accountResolver) OwnerName() string           { return r.a.OwnerName }
+func (r *accountResolver) Email() string               { return r.a.Email }
+func (r *accountResolver) AccountType() string         { return string(r.a.AccountType) }
+func (r *accountResolver) Currency() string            { return r.a.Currency }
+func (r *accountResolver) Balance() Money              { return Money(r.a.Balance) }
+func (r *accountResolver) Held() Money                 { return Money(r.a.Held) }
+func (r *accountResolver) Available() Money            { return Money(r.a.Available()) }
+func (r *accountResolver) OverdraftLimit() Money       { return Money(r.a.OverdraftLimit) }
+func (r *accountResolver) DailyWithdrawalLimit() Money { return Money(r.a.DailyWithdrawalLimit) }
+func (r *accountResolver) Status() string              { return string(r.a.Status) }
+func (r *accountResolver) Frozen() bool                { return r.a.Frozen }
+func (r *accountResolver) CreatedAt() graphql.Time     { return graphql.Time{Time: r.a.CreatedAt} }
+func (r *accountResolver) Version() int32              { return int32(r.a.Version) }
+
+func (r *accountResolver) ClosedAt() *graphql.Time {
+	if r.a.ClosedAt == nil {
+		return nil
+	}
+	return &graphql.Time{Time: *r.a.ClosedAt}
+}
+
+type transactionResolver struct {
+	tx types.Transaction
+}
+
+func (r *transactionResolver) ID() graphql.ID          { return formatID(r.tx.ID) }
+func (r *transactionResolver) AccountID() graphql.ID   { return formatID(r.tx.AccountID) }
+func (r *transactionResolver) Type() string            { return string(r.tx.Type) }
+func (r *transactionResolver) Amount() Money           { return Money(r.tx.Amount) }
+func (r *transactionResolver) BalanceAfter() Money     { return Money(r.tx.BalanceAfter) }
+func (r *transactionResolver) ExchangeRate() *string   { return optional(r.tx.ExchangeRate) }
+func (r *transactionResolver) Reason() *string         { return optional(r.tx.Reason) }
+func (r *transactionResolver) CreatedAt() graphql.Time { return graphql.Time{Time: r.tx.CreatedAt} }
+
+func (r *transactionResolver) ConvertedAmount() *Money {
+	if r.tx.ConvertedAmount == nil {
+		return nil
+	}
+	m := Money(*r.tx.ConvertedAmount)
+	return &m
+}
+
+type transactionPageResolver struct {
+	page types.TransactionPage
+}
+
+func (r *transactionPageResolver) Transactions() []*transactionResolver {
+	out := make([]*transactionResolver, len(r.page.Transactions))
+	for i, tx := range r.page.Transactions {
+		out[i] = &transactionResolver{tx}
+	}
+	return out
+}
+
+func (r *transactionPageResolver) NextCursor() *string { return optional(r.page.NextCursor) }
+
+type transferResolver struct {
+	from, to types.Account
+	ownsTo   bool
+	amount   types.Money
+	fx       *services.Conversion
+}
+
+func (r *transferResolver) From() *accountResolver { return &accountResolver{r.from} }
+func (r *transferResolver) ToID() graphql.ID       { return formatID(r.to.ID) }
+func (r *transferResolver) Amount() Money          { return Money(r.amount) }
+
+func (r *transferResolver) To() *accountResolver {
+	if !r.ownsTo {
+		return nil
+	}
+	return &accountResolver{r.to}
+}
+
+func (r *transferResolver) ConvertedAmount() *Money {
+	if r.fx == nil {
+		return nil
+	}
+	m := Money(r.fx.Credited)
+	return &m
+}
+
+func (r *transferResolver) ExchangeRate() *string {
+	if r.fx == nil {
+		return nil
+	}
+	return &r.fx.Rate
+}
+
+// optional maps the empty string to null.
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
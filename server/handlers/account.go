@@ -0,0 +1,398 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"BankSystemGoLang/clock"
+	"BankSystemGoLang/middleware"
+	"BankSystemGoLang/services"
+	"BankSystemGoLang/types"
+)
+
+var (
+	errForbidden      = errors.New("you do not have access to this account")
+	errInvalidIfMatch = errors.New("If-Match must be an account version such as \"3\"")
+)
+
+type AccountHandler struct {
+	accounts *services.AccountService
+	clock    clock.Clock
+}
+
+func NewAccountHandler(accounts *services.AccountService, clk clock.Clock) *AccountHandler {
+	return &AccountHandler{accounts: accounts, clock: clk}
+}
+
+// Create handles POST /accounts.
+func (h *AccountHandler) Create(c echo.Context) error {
+	var req types.CreateAccountRequest
+	if err := c.Bind(&req); err != nil {
+		return bindError(c, err)
+	}
+
+	account, err := h.accounts.Create(c.Request().Context(), middleware.UserID(c), req)
+	switch {
+	case errors.Is(err, services.ErrDuplicateEmail):
+		return respondError(c, http.StatusConflict, types.CodeDuplicateEmail, err.Error())
+	case errors.Is(err, services.ErrOwnerNameRequired):
+		return respondError(c, http.StatusBadRequest, types.CodeOwnerNameRequired, err.Error())
+	case errors.Is(err, services.ErrInvalidEmail):
+		return respondError(c, http.StatusBadRequest, types.CodeInvalidEmail, err.Error())
+	case errors.Is(err, services.ErrNegativeBalance):
+		return respondError(c, http.StatusBadRequest, types.CodeNegativeBalance, err.Error())
+	case errors.Is(err, services.ErrInvalidOverdraft):
+		return respondError(c, http.StatusBadRequest, types.CodeInvalidOverdraft, err.Error())
+	case errors.Is(err, services.ErrInvalidDailyLimit):
+		return respondError(c, http.StatusBadRequest, types.CodeInvalidDailyLimit, err.Error())
+	case err != nil:
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, account)
+}
+
+// List handles GET /accounts.
+func (h *AccountHandler) List(c echo.Context) error {
+	return h.list(c, middleware.UserID(c))
+}
+
+// ListAll handles GET /admin/accounts. It is admin-only and lists the
+// accounts of every user, or of the one given as ?user_id=.
+func (h *AccountHandler) ListAll(c echo.Context) error {
+	var userID int64
+	if err := echo.QueryParamsBinder(c).Int64("user_id", &userID).BindError(); err != nil || userID < 0 {
+		return respondError(c, http.StatusBadRequest, types.CodeBadRequest, "user_id must be a user ID")
+	}
+	return h.list(c, userID)
+}
+
+// list responds with a page of userID's accounts, or of all accounts when
+// userID is zero.
+func (h *AccountHandler) list(c echo.Context, userID int64) error {
+	limit, offset := services.DefaultAccountLimit, 0
+	if err := echo.QueryParamsBinder(c).
+		Int("limit", &limit).
+		Int("offset", &offset).
+		BindError(); err != nil {
+		return respondError(c, http.StatusBadRequest, types.CodeInvalidPagination, "limit and offset must be integers")
+	}
+	status := types.AccountStatus(c.QueryParam("status"))
+
+	page, err := h.accounts.List(c.Request().Context(), userID, status, limit, offset)
+	if errors.Is(err, services.ErrInvalidStatus) {
+		return respondError(c, http.StatusBadRequest, types.CodeInvalidStatus, err.Error())
+	}
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, page)
+}
+
+// Get handles GET /accounts/:id.
+func (h *AccountHandler) Get(c echo.Context) error {
+	id, err := parseID(c.Param("id"))
+	if err != nil {
+		return respondError(c, http.StatusBadRequest, types.CodeInvalidAccountID, "invalid account id")
+	}
+
+	account, err := ownAccount(c, h.accounts, id)
+	if err != nil {
+		return accessError(c, err)
+	}
+
+	setETag(c, account.Version)
+	return c.JSON(http.StatusOK, account)
+}
+
+// Deposit handles POST /accounts/:id/deposit.
+func (h *AccountHandler) Deposit(c echo.Context) error {
+	id, err := parseID(c.Param("id"))
+	if err != nil {
+		return respondError(c, http.StatusBadRequest, types.CodeInvalidAccountID, "invalid account id")
+	}
+
+	version, err := ifMatch(c)
+	if err != nil {
+		return respondError(c, http.StatusBadRequest, types.CodeInvalidIfMatch, err.Error())
+	}
+
+	var req types.AmountRequest
+	if err := c.Bind(&req); err != nil {
+		return bindError(c, err)
+	}
+
+	if _, err := ownAccount(c, h.accounts, id); err != nil {
+		return accessError(c, err)
+	}
+
+	account, err := h.accounts.Deposit(c.Request().Context(), id, req.Amount, version)
+	switch {
+	case errors.Is(err, services.ErrVersionMismatch):
+		return respondError(c, http.StatusConflict, types.CodeVersionMismatch, err.Error())
+	case errors.Is(err, services.ErrInvalidAmount):
+		return respondError(c, http.StatusBadRequest, types.CodeInvalidAmount, err.Error())
+	case errors.Is(err, services.ErrAccountNotFound):
+		return respondError(c, http.StatusNotFound, types.CodeAccountNotFound, err.Error())
+	case errors.Is(err, services.ErrAccountClosed):
+		return respondError(c, http.StatusConflict, types.CodeAccountClosed, err.Error())
+	case errors.Is(err, services.ErrAccountFrozen):
+		return respondError(c, http.StatusLocked, types.CodeAccountFrozen, err.Error())
+	case err != nil:
+		return err
+	}
+
+	setETag(c, account.Version)
+	return c.JSON(http.StatusOK, types.BalanceResponse{AccountID: account.ID, Balance: account.Balance, Version: account.Version})
+}
+
+// Withdraw handles POST /accounts/:id/withdraw.
+func (h *AccountHandler) Withdraw(c echo.Context) error {
+	id, err := parseID(c.Param("id"))
+	if err != nil {
+		return respondError(c, http.StatusBadRequest, types.CodeInvalidAccountID, "invalid account id")
+	}
+
+	version, err := ifMatch(c)
+	if err != nil {
+		return respondError(c, http.StatusBadRequest, types.CodeInvalidIfMatch, err.Error())
+	}
+
+	var req types.AmountRequest
+	if err := c.Bind(&req); err != nil {
+		return bindError(c, err)
+	}
+
+	if _, err := ownAccount(c, h.accounts, id); err != nil {
+		return accessError(c, err)
+	}
+
+	account, err := h.accounts.Withdraw(c.Request().Context(), id, req.Amount, version)
+	var limitErr *services.DailyLimitError
+	switch {
+	case errors.Is(err, services.ErrVersionMismatch):
+		return respondError(c, http.StatusConflict, types.CodeVersionMismatch, err.Error())
+	case errors.Is(err, services.ErrInvalidAmount):
+		return respondError(c, http.StatusBadRequest, types.CodeInvalidAmount, err.Error())
+	case errors.Is(err, services.ErrAccountNotFound):
+		return respondError(c, http.StatusNotFound, types.CodeAccountNotFound, err.Error())
+	case errors.Is(err, services.ErrAccountClosed):
+		return respondError(c, http.StatusConflict, types.CodeAccountClosed, err.Error())
+	case errors.Is(err, services.ErrAccountFrozen):
+		return respondError(c, http.StatusLocked, types.CodeAccountFrozen, err.Error())
+	case errors.Is(err, services.ErrInsufficientFunds):
+		return respondInsufficientFunds(c, err.Error(), account.Balance)
+	case errors.As(err, &limitErr):
+		return respondDailyLimitExceeded(c, limitErr)
+	case err != nil:
+		return err
+	}
+
+	setETag(c, account.Version)
+	return c.JSON(http.StatusOK, types.BalanceResponse{AccountID: account.ID, Balance: account.Balance, Version: account.Version})
+}
+
+// Update handles PATCH /accounts/:id, changing the owner name or email.
+func (h *AccountHandler) Update(c echo.Context) error {
+	id, err := parseID(c.Param("id"))
+	if err != nil {
+		return respondError(c, http.StatusBadRequest, types.CodeInvalidAccountID, "invalid account id")
+	}
+
+	version, err := ifMatch(c)
+	if err != nil {
+		return respondError(c, http.StatusBadRequest, types.CodeInvalidIfMatch, err.Error())
+	}
+
+	var req types.UpdateAccountRequest
+	if err := c.Bind(&req); err != nil {
+		return bindError(c, err)
+	}
+
+	if _, err := ownAccount(c, h.accounts, id); err != nil {
+		return accessError(c, err)
+	}
+
+	account, err := h.accounts.UpdateContact(c.Request().Context(), id, req, version)
+	switch {
+	case errors.Is(err, services.ErrVersionMismatch):
+		return respondError(c, http.StatusConflict, types.CodeVersionMismatch, err.Error())
+	case errors.Is(err, services.ErrAccountNotFound):
+		return respondError(c, http.StatusNotFound, types.CodeAccountNotFound, err.Error())
+	case errors.Is(err, services.ErrOwnerNameRequired):
+		return respondError(c, http.StatusBadRequest, types.CodeOwnerNameRequired, err.Error())
+	case errors.Is(err, services.ErrInvalidEmail):
+		return respondError(c, http.StatusBadRequest, types.CodeInvalidEmail, err.Error())
+	case errors.Is(err, services.ErrDuplicateEmail):
+		return respondError(c, http.StatusConflict, types.CodeDuplicateEmail, err.Error())
+	case err != nil:
+		return err
+	}
+
+	setETag(c, account.Version)
+	return c.JSON(http.StatusOK, account)
+}
+
+// Close handles DELETE /accounts/:id. Admins may close any user's
+// account on their behalf.
+func (h *AccountHandler) Close(c echo.Context) error {
+	id, err := parseID(c.Param("id"))
+	if err != nil {
+		return respondError(c, http.StatusBadRequest, types.CodeInvalidAccountID, "invalid account id")
+	}
+
+	version, err := ifMatch(c)
+	if err != nil {
+		return respondError(c, http.StatusBadRequest, types.CodeInvalidIfMatch, err.Error())
+	}
+
+	if middleware.Role(c) != types.RoleAdmin {
+		if _, err := ownAccount(c, h.accounts, id); err != nil {
+			return accessError(c, err)
+		}
+	}
+
+	account, err := h.accounts.Close(c.Request().Context(), id, version)
+	switch {
+	case errors.Is(err, services.ErrVersionMismatch):
+		return respondError(c, http.StatusConflict, types.CodeVersionMismatch, err.Error())
+	case errors.Is(err, services.ErrAccountNotFound):
+		return respondError(c, http.StatusNotFound, types.CodeAccountNotFound, err.Error())
+	case errors.Is(err, services.ErrAccountClosed):
+		return respondError(c, http.StatusConflict, types.CodeAccountClosed, err.Error())
+	case errors.Is(err, services.ErrBalanceNotZero):
+		return respondError(c, http.StatusUnprocessableEntity, types.CodeBalanceNotZero, err.Error())
+	case errors.Is(err, services.ErrActiveHolds):
+		return respondError(c, http.StatusUnprocessableEntity, types.CodeActiveHolds, err.Error())
+	case err != nil:
+		return err
+	}
+
+	setETag(c, account.Version)
+	return c.JSON(http.StatusOK, account)
+}
+
+// AccrueInterest handles POST /accounts/:id/accrue-interest. It is
+// admin-only, so the account need not belong to the caller.
+func (h *AccountHandler) AccrueInterest(c echo.Context) error {
+	id, err := parseID(c.Param("id"))
+	if err != nil {
+		return respondError(c, http.StatusBadRequest, types.CodeInvalidAccountID, "invalid account id")
+	}
+
+	result, err := h.accounts.AccrueInterest(c.Request().Context(), id)
+	switch {
+	case errors.Is(err, services.ErrAccountNotFound):
+		return respondError(c, http.StatusNotFound, types.CodeAccountNotFound, err.Error())
+	case errors.Is(err, services.ErrNotSavings):
+		return respondError(c, http.StatusUnprocessableEntity, types.CodeNotSavingsAccount, err.Error())
+	case errors.Is(err, services.ErrAccountClosed):
+		return respondError(c, http.StatusConflict, types.CodeAccountClosed, err.Error())
+	case errors.Is(err, services.ErrAccountFrozen):
+		return respondError(c, http.StatusLocked, types.CodeAccountFrozen, err.Error())
+	case err != nil:
+		return err
+	}
+
+	// An accrual that posts no interest is a no-op nobody needs to audit.
+	if result.Interest == 0 {
+		middleware.SkipAudit(c)
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+// Transactions handles GET /accounts/:id/transactions. Pages are selected
+// by limit and either offset or the cursor from a previous page.
+func (h *AccountHandler) Transactions(c echo.Context) error {
+	id, err := parseID(c.Param("id"))
+	if err != nil {
+		return respondError(c, http.StatusBadRequest, types.CodeInvalidAccountID, "invalid account id")
+	}
+
+	limit, offset := services.DefaultTransactionLimit, 0
+	if err := echo.QueryParamsBinder(c).
+		Int("limit", &limit).
+		Int("offset", &offset).
+		BindError(); err != nil {
+		return respondError(c, http.StatusBadRequest, types.CodeInvalidPagination, "limit and offset must be integers")
+	}
+
+	if _, err := ownAccount(c, h.accounts, id); err != nil {
+		return accessError(c, err)
+	}
+
+	page, err := h.accounts.Transactions(c.Request().Context(), id, limit, offset, c.QueryParam("cursor"))
+	switch {
+	case errors.Is(err, services.ErrInvalidPagination), errors.Is(err, services.ErrCursorWithOffset):
+		return respondError(c, http.StatusBadRequest, types.CodeInvalidPagination, err.Error())
+	case errors.Is(err, services.ErrInvalidCursor):
+		return respondError(c, http.StatusBadRequest, types.CodeInvalidCursor, err.Error())
+	case errors.Is(err, services.ErrAccountNotFound):
+		return respondError(c, http.StatusNotFound, types.CodeAccountNotFound, err.Error())
+	case err != nil:
+		return err
+	}
+
+	return c.JSON(http.StatusOK, page)
+}
+
+// ownAccount loads the account and checks that it belongs to the
+// authenticated user, returning errForbidden when it does not.
+func ownAccount(c echo.Context, accounts *services.AccountService, id int64) (types.Account, error) {
+	account, err := accounts.Get(c.Request().Context(), id)
+	if err != nil {
+		return types.Account{}, err
+	}
+	if account.UserID != middleware.UserID(c) {
+		return types.Account{}, errForbidden
+	}
+	return account, nil
+}
+
+// accessError turns an ownAccount failure into a response.
+func accessError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, services.ErrAccountNotFound):
+		return respondError(c, http.StatusNotFound, types.CodeAccountNotFound, err.Error())
+	case errors.Is(err, errForbidden):
+		return respondError(c, http.StatusForbidden, types.CodeForbidden, err.Error())
+	}
+	return err
+}
+
+// ifMatch returns the account version the client's If-Match header
+// requires, or 0 when there is none or it is "*". The version is accepted
+// with or without the quotes and weak prefix of the ETag it came from.
+func ifMatch(c echo.Context) (int64, error) {
+	raw := strings.TrimSpace(c.Request().Header.Get(middleware.HeaderIfMatch))
+	if raw == "" || raw == "*" {
+		return 0, nil
+	}
+	version, err := strconv.ParseInt(strings.Trim(strings.TrimPrefix(raw, "W/"), `"`), 10, 64)
+	if err != nil || version <= 0 {
+		return 0, errInvalidIfMatch
+	}
+	return version, nil
+}
+
+// setETag sends the account version as a strong ETag for later If-Match.
+func setETag(c echo.Context, version int64) {
+	c.Response().Header().Set(middleware.HeaderETag, `"`+strconv.FormatInt(version, 10)+`"`)
+}
+
+// parseID converts a path parameter into a positive account ID.
+func parseID(raw string) (int64, error) {
+	id, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	if id <= 0 {
+		return 0, strconv.ErrRange
+	}
+	return id, nil
+}
@@ -0,0 +1,247 @@
+package handlers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"BankSystemGoLang/types"
+)
+
+func TestCreateAccount(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login(t, "alice@example.com")
+
+	rec := s.do(token, http.MethodPost, "/accounts", `{"owner_name":"Alice","email":"alice@example.com","initial_balance":"25.50"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want 201: %s", rec.Code, rec.Body)
+	}
+	var body map[string]any
+	decode(t, rec, &body)
+	if id, ok := body["id"].(float64); !ok || id <= 0 {
+		t.Errorf("id = %v, want a positive ID", body["id"])
+	}
+	if created, ok := body["created_at"].(string); !ok || created == "" {
+		t.Errorf("created_at = %v, want a timestamp", body["created_at"])
+	}
+	if body["balance"] != "25.50" {
+		t.Errorf("balance = %v, want 25.50", body["balance"])
+	}
+
+	rec = s.do(token, http.MethodPost, "/accounts", `{"owner_name":"Alice again","email":"alice@example.com"}`)
+	expectError(t, rec, http.StatusConflict, types.CodeDuplicateEmail)
+}
+
+func TestGetAccount(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login(t, "alice@example.com")
+	account := s.openAccount(t, token, `{"owner_name":"Alice","email":"alice@example.com"}`)
+
+	tests := []struct {
+		name   string
+		path   string
+		status int
+		code   string
+	}{
+		{name: "found", path: "/accounts/1", status: http.StatusOK},
+		{name: "not found", path: "/accounts/99", status: http.StatusNotFound, code: types.CodeAccountNotFound},
+		{name: "malformed id", path: "/accounts/abc", status: http.StatusBadRequest, code: types.CodeInvalidAccountID},
+		{name: "zero id", path: "/accounts/0", status: http.StatusBadRequest, code: types.CodeInvalidAccountID},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := s.do(token, http.MethodGet, tt.path, "")
+			if tt.code != "" {
+				expectError(t, rec, tt.status, tt.code)
+				return
+			}
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.status, rec.Body)
+			}
+			var got types.Account
+			decode(t, rec, &got)
+			if got.ID != account.ID || got.OwnerName != "Alice" {
+				t.Errorf("got account %d owned by %q, want %d owned by Alice", got.ID, got.OwnerName, account.ID)
+			}
+		})
+	}
+}
+
+func TestWithdrawOverlapping(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login(t, "alice@example.com")
+	account := s.openAccount(t, token, `{"owner_name":"Alice","email":"alice@example.com","initial_balance":"50.00"}`)
+
+	var wg sync.WaitGroup
+	recs := make([]*httptest.ResponseRecorder, 2)
+	for i := range recs {
+		wg.Go(func() {
+			recs[i] = s.do(token, http.MethodPost, "/accounts/1/withdraw", `{"amount":"50.00"}`)
+		})
+	}
+	wg.Wait()
+
+	var ok, rejected *httptest.ResponseRecorder
+	for _, rec := range recs {
+		switch rec.Code {
+		case http.StatusOK:
+			ok = rec
+		case http.StatusUnprocessableEntity:
+			rejected = rec
+		}
+	}
+	if ok == nil || rejected == nil {
+		t.Fatalf("statuses = %d and %d, want one 200 and one 422", recs[0].Code, recs[1].Code)
+	}
+	body := expectError(t, rejected, http.StatusUnprocessableEntity, types.CodeInsufficientFunds)
+	if body.Balance == nil || *body.Balance != 0 {
+		t.Errorf("balance in 422 body = %v, want 0.00", body.Balance)
+	}
+	if got := s.balance(t, account.ID); got != 0 {
+		t.Errorf("balance = %s, want 0.00", got)
+	}
+}
+
+func TestWithdrawFromAnotherUsersAccount(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.login(t, "alice@example.com")
+	bob := s.login(t, "bob@example.com")
+	account := s.openAccount(t, bob, `{"owner_name":"Bob","email":"bob@example.com","initial_balance":"80.00"}`)
+
+	rec := s.do(alice, http.MethodPost, "/accounts/1/withdraw", `{"amount":"10.00"}`)
+	expectError(t, rec, http.StatusForbidden, types.CodeForbidden)
+	if got := s.balance(t, account.ID); got != 80_00 {
+		t.Errorf("balance = %s, want 80.00", got)
+	}
+}
+
+func TestCloseAccount(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login(t, "alice@example.com")
+	account := s.openAccount(t, token, `{"owner_name":"Alice","email":"alice@example.com","initial_balance":"10.00"}`)
+
+	rec := s.do(token, http.MethodDelete, "/accounts/1", "")
+	expectError(t, rec, http.StatusUnprocessableEntity, types.CodeBalanceNotZero)
+
+	if rec := s.do(token, http.MethodPost, "/accounts/1/withdraw", `{"amount":"10.00"}`); rec.Code != http.StatusOK {
+		t.Fatalf("withdraw = %d %s", rec.Code, rec.Body)
+	}
+	rec = s.do(token, http.MethodDelete, "/accounts/1", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("close = %d %s", rec.Code, rec.Body)
+	}
+	var closed types.Account
+	decode(t, rec, &closed)
+	if closed.Status != types.AccountClosed || closed.ClosedAt == nil {
+		t.Errorf("closed account = %+v, want status closed with closed_at", closed)
+	}
+
+	rec = s.do(token, http.MethodPost, "/accounts/1/deposit", `{"amount":"5.00"}`)
+	expectError(t, rec, http.StatusConflict, types.CodeAccountClosed)
+	if got := s.balance(t, account.ID); got != 0 {
+		t.Errorf("balance = %s, want 0.00", got)
+	}
+
+	// A closed account stays readable.
+	if rec := s.do(token, http.MethodGet, "/accounts/1", ""); rec.Code != http.StatusOK {
+		t.Errorf("GET closed account = %d, want 200", rec.Code)
+	}
+}
+
+func TestDepositIfMatch(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login(t, "alice@example.com")
+	account := s.openAccount(t, token, `{"owner_name":"Alice","email":"alice@example.com","initial_balance":"10.00"}`)
+
+	rec := s.do(token, http.MethodGet, "/accounts/1", "")
+	etag := rec.Header().Get("ETag")
+	if etag != `"1"` {
+		t.Fatalf("ETag = %q, want \"1\"", etag)
+	}
+
+	rec = s.do(token, http.MethodPost, "/accounts/1/deposit", `{"amount":"5.00"}`, "If-Match", etag)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("deposit with a current If-Match = %d %s", rec.Code, rec.Body)
+	}
+	if got := rec.Header().Get("ETag"); got != `"2"` {
+		t.Errorf("ETag after deposit = %q, want \"2\"", got)
+	}
+
+	// The same header is now stale.
+	rec = s.do(token, http.MethodPost, "/accounts/1/deposit", `{"amount":"5.00"}`, "If-Match", etag)
+	expectError(t, rec, http.StatusConflict, types.CodeVersionMismatch)
+	if got := s.balance(t, account.ID); got != 15_00 {
+		t.Errorf("balance = %s, want 15.00", got)
+	}
+
+	rec = s.do(token, http.MethodPost, "/accounts/1/deposit", `{"amount":"5.00"}`, "If-Match", "latest")
+	expectError(t, rec, http.StatusBadRequest, types.CodeInvalidIfMatch)
+}
+
+func TestDepositIntoFrozenAccount(t *testing.T) {
+	s := newTestServer(t)
+	admin := s.login(t, testAdminEmail)
+	token := s.login(t, "alice@example.com")
+	account := s.openAccount(t, token, `{"owner_name":"Alice","email":"alice@example.com","initial_balance":"10.00"}`)
+
+	// Only an admin may freeze.
+	rec := s.do(token, http.MethodPost, "/accounts/1/freeze", `{"reason":"suspected fraud"}`)
+	expectError(t, rec, http.StatusForbidden, types.CodeForbidden)
+
+	if rec := s.do(admin, http.MethodPost, "/accounts/1/freeze", `{"reason":"suspected fraud"}`); rec.Code != http.StatusOK {
+		t.Fatalf("freeze = %d %s", rec.Code, rec.Body)
+	}
+	rec = s.do(token, http.MethodPost, "/accounts/1/deposit", `{"amount":"5.00"}`)
+	expectError(t, rec, http.StatusLocked, types.CodeAccountFrozen)
+	if got := s.balance(t, account.ID); got != 10_00 {
+		t.Errorf("balance while frozen = %s, want 10.00", got)
+	}
+
+	if rec := s.do(admin, http.MethodPost, "/accounts/1/unfreeze", `{"reason":"cleared"}`); rec.Code != http.StatusOK {
+		t.Fatalf("unfreeze = %d %s", rec.Code, rec.Body)
+	}
+	if rec := s.do(token, http.MethodPost, "/accounts/1/deposit", `{"amount":"5.00"}`); rec.Code != http.StatusOK {
+		t.Fatalf("deposit after unfreeze = %d %s", rec.Code, rec.Body)
+	}
+	if got := s.balance(t, account.ID); got != 15_00 {
+		t.Errorf("balance after unfreeze = %s, want 15.00", got)
+	}
+}
+
+func TestUpdateAccountContact(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login(t, "alice@example.com")
+	account := s.openAccount(t, token, `{"owner_name":"Alice","email":"alice@example.com","initial_balance":"10.00"}`)
+
+	rec := s.do(token, http.MethodPatch, "/accounts/1", `{"email":"alice@work.example.com"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("PATCH email = %d %s", rec.Code, rec.Body)
+	}
+	var updated types.Account
+	decode(t, rec, &updated)
+	if updated.Email != "alice@work.example.com" || updated.OwnerName != "Alice" || updated.Version != account.Version+1 {
+		t.Errorf("after PATCH: %+v, want the new email, owner_name unchanged and the version bumped", updated)
+	}
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "balance", body: `{"balance":"1000000.00"}`},
+		{name: "id alongside an allowed field", body: `{"owner_name":"Mallory","id":2}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := s.do(token, http.MethodPatch, "/accounts/1", tt.body)
+			expectError(t, rec, http.StatusBadRequest, types.CodeImmutableField)
+		})
+	}
+
+	rec = s.do(token, http.MethodGet, "/accounts/1", "")
+	var got types.Account
+	decode(t, rec, &got)
+	if got.OwnerName != "Alice" || got.Balance != 10_00 || got.Version != updated.Version {
+		t.Errorf("after rejected PATCHes: %+v, want the account unchanged", got)
+	}
+}
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"BankSystemGoLang/clock"
+	"BankSystemGoLang/services"
+	"BankSystemGoLang/types"
+)
+
+type AuditHandler struct {
+	audit *services.AuditService
+	clock clock.Clock
+}
+
+func NewAuditHandler(audit *services.AuditService, clk clock.Clock) *AuditHandler {
+	return &AuditHandler{audit: audit, clock: clk}
+}
+
+// List handles GET /audit-log. It is admin-only and lists the entries
+// between the from and to dates, the last DefaultAuditDays by default.
+func (h *AuditHandler) List(c echo.Context) error {
+	to := h.clock.Now().UTC()
+	from := to.AddDate(0, 0, 1-services.DefaultAuditDays)
+	if err := echo.QueryParamsBinder(c).
+		Time("from", &from, dateLayout).
+		Time("to", &to, dateLayout).
+		BindError(); err != nil {
+		return respondError(c, http.StatusBadRequest, types.CodeInvalidDate, "from and to must be dates formatted as YYYY-MM-DD")
+	}
+
+	log, err := h.audit.List(c.Request().Context(), from, to)
+	if errors.Is(err, services.ErrInvalidDateRange) {
+		return respondError(c, http.StatusBadRequest, types.CodeInvalidDateRange, err.Error())
+	}
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, log)
+}
@@ -0,0 +1,81 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"BankSystemGoLang/types"
+)
+
+// auditLog returns the entries GET /audit-log lists by default.
+func (s *testServer) auditLog(t *testing.T, admin string) []types.AuditEntry {
+	t.Helper()
+	rec := s.do(admin, http.MethodGet, "/audit-log", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /audit-log = %d %s", rec.Code, rec.Body)
+	}
+	var log types.AuditLog
+	decode(t, rec, &log)
+	return log.Entries
+}
+
+func TestFreezeIsAudited(t *testing.T) {
+	s := newTestServer(t)
+	admin := s.login(t, testAdminEmail)
+	token := s.login(t, "alice@example.com")
+	account := s.openAccount(t, token, `{"owner_name":"Alice","email":"alice@example.com"}`)
+	adminUser, err := s.store.GetUserByEmail(context.Background(), testAdminEmail)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rec := s.do(admin, http.MethodPost, "/accounts/1/freeze", `{"reason": "suspected fraud"}`); rec.Code != http.StatusOK {
+		t.Fatalf("freeze = %d %s", rec.Code, rec.Body)
+	}
+
+	entries := s.auditLog(t, admin)
+	if len(entries) != 1 {
+		t.Fatalf("audit log = %+v, want one entry", entries)
+	}
+	got := entries[0]
+	if got.AdminID != adminUser.ID || got.Action != types.AuditFreeze || got.AccountID != account.ID {
+		t.Errorf("entry = %+v, want admin %d freezing account %d", got, adminUser.ID, account.ID)
+	}
+	if string(got.Request) != `{"reason":"suspected fraud"}` {
+		t.Errorf("request = %s, want the compacted freeze body", got.Request)
+	}
+}
+
+func TestNoOpAccrualIsNotAudited(t *testing.T) {
+	s := newTestServer(t)
+	admin := s.login(t, testAdminEmail)
+	token := s.login(t, "alice@example.com")
+	s.openAccount(t, token, `{"owner_name":"Alice","email":"alice@example.com","account_type":"savings","initial_balance":"1000.00"}`)
+
+	accrue := func() types.InterestResponse {
+		t.Helper()
+		rec := s.do(admin, http.MethodPost, "/accounts/1/accrue-interest", "")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("accrue = %d %s", rec.Code, rec.Body)
+		}
+		var res types.InterestResponse
+		decode(t, rec, &res)
+		return res
+	}
+
+	s.clock.Advance(24 * time.Hour)
+	admin = s.token(t, testAdminEmail)
+	if res := accrue(); res.Days != 1 {
+		t.Fatalf("first accrual covered %d days, want 1", res.Days)
+	}
+	if res := accrue(); res.Days != 0 {
+		t.Fatalf("second accrual the same day covered %d days, want 0", res.Days)
+	}
+
+	entries := s.auditLog(t, admin)
+	if len(entries) != 1 || entries[0].Action != types.AuditAccrueInterest {
+		t.Errorf("audit log = %+v, want only the accrual that posted interest", entries)
+	}
+}
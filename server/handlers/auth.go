@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"BankSystemGoLang/middleware"
+	"BankSystemGoLang/services"
+	"BankSystemGoLang/types"
+)
+
+type AuthHandler struct {
+	auth         *services.AuthService
+	verification *services.VerificationService
+	log          *zap.Logger
+}
+
+func NewAuthHandler(auth *services.AuthService, verification *services.VerificationService, log *zap.Logger) *AuthHandler {
+	return &AuthHandler{auth: auth, verification: verification, log: log}
+}
+
+// Register handles POST /register and emails the new user a verification
+// link. When the email cannot be sent the failure is logged and the user
+// is still created, answering 201, and can ask for another link through
+// POST /verify/resend.
+func (h *AuthHandler) Register(c echo.Context) error {
+	var req types.RegisterRequest
+	if err := c.Bind(&req); err != nil {
+		return bindError(c, err)
+	}
+
+	user, err := h.auth.Register(c.Request().Context(), req)
+	switch {
+	case errors.Is(err, services.ErrInvalidEmail):
+		return respondError(c, http.StatusBadRequest, types.CodeInvalidEmail, err.Error())
+	case errors.Is(err, services.ErrPasswordTooShort):
+		return respondError(c, http.StatusBadRequest, types.CodePasswordTooShort, err.Error())
+	case errors.Is(err, services.ErrUserExists):
+		return respondError(c, http.StatusConflict, types.CodeUserExists, err.Error())
+	case err != nil:
+		return err
+	}
+
+	if err := h.verification.Send(c.Request().Context(), user); err != nil {
+		h.log.Error("send verification email",
+			zap.String("request_id", middleware.RequestIDFrom(c)),
+			zap.Int64("user_id", user.ID),
+			zap.Error(err))
+	}
+	return c.JSON(http.StatusCreated, user)
+}
+
+// Login handles POST /login.
+func (h *AuthHandler) Login(c echo.Context) error {
+	var req types.LoginRequest
+	if err := c.Bind(&req); err != nil {
+		return bindError(c, err)
+	}
+
+	resp, err := h.auth.Login(c.Request().Context(), req)
+	switch {
+	case errors.Is(err, services.ErrInvalidCredentials):
+		return respondError(c, http.StatusUnauthorized, types.CodeInvalidCredentials, err.Error())
+	case errors.Is(err, services.ErrEmailNotVerified):
+		return respondError(c, http.StatusForbidden, types.CodeEmailNotVerified, err.Error())
+	case err != nil:
+		return err
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// Verify handles GET /verify?token=, the link emailed at registration.
+func (h *AuthHandler) Verify(c echo.Context) error {
+	token := c.QueryParam("token")
+	if token == "" {
+		return respondError(c, http.StatusBadRequest, types.CodeVerificationInvalid, "token is required")
+	}
+
+	user, err := h.verification.Verify(c.Request().Context(), token)
+	switch {
+	case errors.Is(err, services.ErrVerificationInvalid):
+		return respondError(c, http.StatusBadRequest, types.CodeVerificationInvalid, err.Error())
+	case errors.Is(err, services.ErrVerificationExpired):
+		return respondError(c, http.StatusBadRequest, types.CodeVerificationExpired, err.Error())
+	case err != nil:
+		return err
+	}
+
+	return c.JSON(http.StatusOK, user)
+}
+
+// ResendVerification handles POST /verify/resend. It answers 202 whether
+// or not the email belongs to an unverified user.
+func (h *AuthHandler) ResendVerification(c echo.Context) error {
+	var req types.ResendVerificationRequest
+	if err := c.Bind(&req); err != nil {
+		return bindError(c, err)
+	}
+
+	if err := h.verification.Resend(c.Request().Context(), req.Email); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusAccepted)
+}
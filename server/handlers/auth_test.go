@@ -0,0 +1,73 @@
+package handlers_test
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"regexp"
+	"testing"
+
+	"BankSystemGoLang/types"
+)
+
+var verifyLink = regexp.MustCompile(`http://bank\.test/verify\?token=\S+`)
+
+func TestRegisterVerifyLogin(t *testing.T) {
+	s := newTestServer(t)
+	credentials := `{"email":"alice@example.com","password":"` + testPassword + `"}`
+
+	if rec := s.do("", http.MethodPost, "/register", credentials); rec.Code != http.StatusCreated {
+		t.Fatalf("register = %d %s", rec.Code, rec.Body)
+	}
+	rec := s.do("", http.MethodPost, "/login", credentials)
+	expectError(t, rec, http.StatusForbidden, types.CodeEmailNotVerified)
+
+	sent := s.mail.sent()
+	if len(sent) != 1 || sent[0].to != "alice@example.com" {
+		t.Fatalf("sent %+v, want one message to alice@example.com", sent)
+	}
+	link, err := url.Parse(verifyLink.FindString(sent[0].body))
+	if err != nil || link.Query().Get("token") == "" {
+		t.Fatalf("no verification link in %q", sent[0].body)
+	}
+	if rec := s.do("", http.MethodGet, "/verify?"+link.RawQuery, ""); rec.Code != http.StatusOK {
+		t.Fatalf("verify = %d %s", rec.Code, rec.Body)
+	}
+	rec = s.do("", http.MethodGet, "/verify?"+link.RawQuery, "")
+	expectError(t, rec, http.StatusBadRequest, types.CodeVerificationInvalid)
+
+	rec = s.do("", http.MethodPost, "/login", credentials)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("login after verifying = %d %s", rec.Code, rec.Body)
+	}
+	var res types.LoginResponse
+	decode(t, rec, &res)
+	if rec := s.do(res.Token, http.MethodGet, "/accounts", ""); rec.Code != http.StatusOK {
+		t.Errorf("GET /accounts with the new token = %d %s", rec.Code, rec.Body)
+	}
+}
+
+func TestRegisterWhenMailFails(t *testing.T) {
+	s := newTestServer(t)
+	s.mail.err = errors.New("smtp: connection refused")
+	credentials := `{"email":"alice@example.com","password":"` + testPassword + `"}`
+
+	rec := s.do("", http.MethodPost, "/register", credentials)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("register = %d %s, want 201 though the email failed", rec.Code, rec.Body)
+	}
+	var user types.User
+	decode(t, rec, &user)
+	if user.ID <= 0 || user.Email != "alice@example.com" {
+		t.Errorf("user = %+v, want the created user", user)
+	}
+
+	// Once mail works again the user can ask for another link.
+	s.mail.err = nil
+	if rec := s.do("", http.MethodPost, "/verify/resend", `{"email":"alice@example.com"}`); rec.Code/100 != 2 {
+		t.Fatalf("resend = %d %s", rec.Code, rec.Body)
+	}
+	if sent := s.mail.sent(); len(sent) != 1 {
+		t.Errorf("sent %d messages after the resend, want 1", len(sent))
+	}
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+
+	"BankSystemGoLang/types"
+)
+
+// ValidationError lists every field of a request body that failed its
+// `validate` struct tag.
+type ValidationError struct {
+	Fields []types.FieldError
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%d invalid field(s)", len(e.Fields))
+}
+
+// Binder binds requests with Echo's default binder and then validates the
+// result against its `validate` struct tags.
+type Binder struct {
+	echo.DefaultBinder
+	validate *validator.Validate
+}
+
+func NewBinder() *Binder {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	// Report fields by their JSON name so clients can match them up.
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	return &Binder{validate: v}
+}
+
+func (b *Binder) Bind(i any, c echo.Context) error {
+	if err := b.DefaultBinder.Bind(i, c); err != nil {
+		return err
+	}
+
+	err := b.validate.Struct(i)
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		return nil
+	}
+
+	fields := make([]types.FieldError, 0, len(verrs))
+	for _, fe := range verrs {
+		fields = append(fields, types.FieldError{Field: fieldPath(fe), Message: fieldMessage(fe)})
+	}
+	return &ValidationError{Fields: fields}
+}
+
+// fieldPath names the field by its JSON path, such as transfers[2].amount
+// for an element of a list, leaving out the request type's own name.
+func fieldPath(fe validator.FieldError) string {
+	_, path, _ := strings.Cut(fe.Namespace(), ".")
+	return path
+}
+
+func fieldMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email"
+	case "gt":
+		return "must be greater than " + fe.Param()
+	case "gte":
+		return "must be greater than or equal to " + fe.Param()
+	case "http_url":
+		return "must be an http or https URL"
+	case "min":
+		return "must have at least " + fe.Param() + " item(s)"
+	case "max":
+		return "must have at most " + fe.Param() + " item(s)"
+	case "iso4217":
+		return "must be an ISO 4217 currency code"
+	case "oneof":
+		return "must be one of " + strings.ReplaceAll(fe.Param(), " ", ", ")
+	}
+	return "failed the " + fe.Tag() + " check"
+}
+
+// bindError writes the response for a failed c.Bind call.
+func bindError(c echo.Context, err error) error {
+	var verr *ValidationError
+	if errors.As(err, &verr) {
+		body := errorResponse(c, types.CodeValidationFailed, "request validation failed")
+		body.Fields = verr.Fields
+		return c.JSON(http.StatusBadRequest, body)
+	}
+	var ierr *types.ImmutableFieldError
+	if errors.As(err, &ierr) {
+		return respondError(c, http.StatusBadRequest, types.CodeImmutableField, ierr.Error())
+	}
+	return respondError(c, http.StatusBadRequest, types.CodeBadRequest, "invalid request body")
+}
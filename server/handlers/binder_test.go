@@ -0,0 +1,36 @@
+package handlers_test
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+
+	"BankSystemGoLang/types"
+)
+
+func TestBindReportsEveryInvalidField(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login(t, "alice@example.com")
+
+	rec := s.do(token, http.MethodPost, "/accounts", `{
+		"email": "not-an-email",
+		"initial_balance": "-1.00",
+		"account_type": "brokerage",
+		"currency": "ZZZ",
+		"overdraft_limit": "-5.00"
+	}`)
+	body := expectError(t, rec, http.StatusBadRequest, types.CodeValidationFailed)
+
+	var got []string
+	for _, f := range body.Fields {
+		if f.Message == "" {
+			t.Errorf("field %s has no message", f.Field)
+		}
+		got = append(got, f.Field)
+	}
+	for _, want := range []string{"owner_name", "email", "initial_balance", "account_type", "currency", "overdraft_limit"} {
+		if !slices.Contains(got, want) {
+			t.Errorf("fields = %v, missing %s", got, want)
+		}
+	}
+}
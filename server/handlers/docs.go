@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// DocsHandler serves the API description built at startup.
+type DocsHandler struct {
+	spec []byte
+	page []byte
+}
+
+// NewDocsHandler serves spec, an OpenAPI document, and page, the HTML that
+// renders it.
+func NewDocsHandler(spec, page []byte) *DocsHandler {
+	return &DocsHandler{spec: spec, page: page}
+}
+
+// Spec handles GET /openapi.json.
+func (h *DocsHandler) Spec(c echo.Context) error {
+	return c.JSONBlob(http.StatusOK, h.spec)
+}
+
+// UI handles GET /docs.
+func (h *DocsHandler) UI(c echo.Context) error {
+	return c.HTMLBlob(http.StatusOK, h.page)
+}
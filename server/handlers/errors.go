@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"BankSystemGoLang/middleware"
+	"BankSystemGoLang/services"
+	"BankSystemGoLang/types"
+)
+
+// respondError writes the standard error body.
+func respondError(c echo.Context, status int, code, message string) error {
+	return c.JSON(status, errorResponse(c, code, message))
+}
+
+// respondInsufficientFunds writes a 422 that also reports the balance the
+// request was checked against.
+func respondInsufficientFunds(c echo.Context, message string, balance types.Money) error {
+	body := errorResponse(c, types.CodeInsufficientFunds, message)
+	body.Balance = &balance
+	return c.JSON(http.StatusUnprocessableEntity, body)
+}
+
+// respondDailyLimitExceeded writes a 422 that also reports how much may
+// still be withdrawn today.
+func respondDailyLimitExceeded(c echo.Context, err *services.DailyLimitError) error {
+	body := errorResponse(c, types.CodeDailyLimitExceeded, err.Error())
+	body.Remaining = &err.Remaining
+	return c.JSON(http.StatusUnprocessableEntity, body)
+}
+
+// errorResponse builds an error body tagged with the request ID, so users
+// can quote it when contacting support.
+func errorResponse(c echo.Context, code, message string) types.ErrorResponse {
+	return types.ErrorResponse{
+		Code:      code,
+		Error:     message,
+		RequestID: middleware.RequestIDFrom(c),
+	}
+}
+
+// NewHTTPErrorHandler replaces Echo's default handler so that unknown
+// routes, framework errors and unexpected failures share the ErrorResponse
+// shape. Internal error details are logged, never returned to the client.
+func NewHTTPErrorHandler(log *zap.Logger) echo.HTTPErrorHandler {
+	return func(err error, c echo.Context) {
+		handleError(log, err, c)
+	}
+}
+
+func handleError(log *zap.Logger, err error, c echo.Context) {
+	if c.Response().Committed {
+		// Too late to tell the client, but not to tell the operators.
+		log.Error("request failed after the response was sent",
+			zap.String("request_id", middleware.RequestIDFrom(c)), zap.Error(err))
+		return
+	}
+
+	status, code, message := http.StatusInternalServerError, types.CodeInternal, "internal server error"
+
+	var he *echo.HTTPError
+	if errors.Is(err, context.Canceled) {
+		// The client has gone away; there is nobody left to answer. With
+		// no response written, Idempotent releases the request's key.
+		log.Info("request cancelled by the client", zap.String("request_id", middleware.RequestIDFrom(c)))
+		return
+	} else if errors.Is(err, context.DeadlineExceeded) {
+		// Any store call can run out of the request's time, so like
+		// conflicts this is mapped once here.
+		log.Warn("request timed out", zap.String("request_id", middleware.RequestIDFrom(c)), zap.Error(err))
+		status, code, message = http.StatusGatewayTimeout, types.CodeTimeout, "request timed out"
+	} else if errors.Is(err, services.ErrConcurrentUpdate) {
+		// Any account write can lose the optimistic version check, so this
+		// is mapped once here rather than in every handler.
+		status, code, message = http.StatusConflict, types.CodeConcurrentUpdate, err.Error()
+	} else if errors.As(err, &he) && he.Code != http.StatusInternalServerError {
+		status = he.Code
+		code = statusCode(he.Code)
+		message = http.StatusText(he.Code)
+		if m, ok := he.Message.(string); ok {
+			message = m
+		}
+	} else {
+		log.Error("request failed", zap.String("request_id", middleware.RequestIDFrom(c)), zap.Error(err))
+	}
+
+	if c.Request().Method == http.MethodHead {
+		err = c.NoContent(status)
+	} else {
+		err = respondError(c, status, code, message)
+	}
+	if err != nil {
+		log.Error("write error response", zap.String("request_id", middleware.RequestIDFrom(c)), zap.Error(err))
+	}
+}
+
+func statusCode(status int) string {
+	switch status {
+	case http.StatusNotFound:
+		return types.CodeNotFound
+	case http.StatusMethodNotAllowed:
+		return types.CodeMethodNotAllowed
+	case http.StatusUnauthorized:
+		return types.CodeUnauthorized
+	case http.StatusForbidden:
+		return types.CodeForbidden
+	case http.StatusBadRequest:
+		return types.CodeBadRequest
+	}
+	return strings.ToUpper(strings.ReplaceAll(http.StatusText(status), " ", "_"))
+}
@@ -0,0 +1,66 @@
+package handlers_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"BankSystemGoLang/handlers"
+	"BankSystemGoLang/middleware"
+	"BankSystemGoLang/types"
+)
+
+func TestUnknownRouteReturnsJSON(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login(t, "alice@example.com")
+
+	tests := []struct {
+		name   string
+		token  string
+		method string
+		path   string
+		status int
+		code   string
+	}{
+		{name: "unknown path", method: http.MethodGet, path: "/no-such-route", status: http.StatusNotFound, code: types.CodeNotFound},
+		{name: "unknown path with a token", token: token, method: http.MethodGet, path: "/no-such-route", status: http.StatusNotFound, code: types.CodeNotFound},
+		{name: "unknown path under /accounts", method: http.MethodGet, path: "/accounts/1/nothing", status: http.StatusNotFound, code: types.CodeNotFound},
+		{name: "wrong method", method: http.MethodPut, path: "/health", status: http.StatusMethodNotAllowed, code: types.CodeMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := s.do(tt.token, tt.method, tt.path, "")
+			if ct := rec.Header().Get(echo.HeaderContentType); !strings.HasPrefix(ct, echo.MIMEApplicationJSON) {
+				t.Errorf("Content-Type = %q, want JSON", ct)
+			}
+			body := expectError(t, rec, tt.status, tt.code)
+			if body.Error == "" || body.RequestID == "" {
+				t.Errorf("body = %+v, want a message and a request ID", body)
+			}
+		})
+	}
+}
+
+func TestInternalErrorIsHidden(t *testing.T) {
+	e := echo.New()
+	e.HTTPErrorHandler = handlers.NewHTTPErrorHandler(zap.NewNop())
+	e.Use(middleware.RequestID())
+	e.GET("/fail", func(echo.Context) error {
+		return errors.New("database password is hunter2")
+	})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/fail", nil))
+	body := expectError(t, rec, http.StatusInternalServerError, types.CodeInternal)
+	if strings.Contains(rec.Body.String(), "hunter2") {
+		t.Errorf("internal error details leaked: %s", rec.Body)
+	}
+	if body.RequestID == "" {
+		t.Error("error body has no request ID")
+	}
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"BankSystemGoLang/services"
+	"BankSystemGoLang/types"
+)
+
+// Freeze handles POST /accounts/:id/freeze. It is admin-only, so the
+// account need not belong to the caller.
+func (h *AccountHandler) Freeze(c echo.Context) error {
+	return h.setFrozen(c, h.accounts.Freeze)
+}
+
+// Unfreeze handles POST /accounts/:id/unfreeze. It is admin-only.
+func (h *AccountHandler) Unfreeze(c echo.Context) error {
+	return h.setFrozen(c, h.accounts.Unfreeze)
+}
+
+func (h *AccountHandler) setFrozen(c echo.Context, set func(context.Context, int64, string) (types.Account, error)) error {
+	id, err := parseID(c.Param("id"))
+	if err != nil {
+		return respondError(c, http.StatusBadRequest, types.CodeInvalidAccountID, "invalid account id")
+	}
+
+	var req types.FreezeRequest
+	if err := c.Bind(&req); err != nil {
+		return bindError(c, err)
+	}
+
+	account, err := set(c.Request().Context(), id, req.Reason)
+	switch {
+	case errors.Is(err, services.ErrReasonMissing):
+		return respondError(c, http.StatusBadRequest, types.CodeValidationFailed, err.Error())
+	case errors.Is(err, services.ErrAccountNotFound):
+		return respondError(c, http.StatusNotFound, types.CodeAccountNotFound, err.Error())
+	case errors.Is(err, services.ErrAccountClosed):
+		return respondError(c, http.StatusConflict, types.CodeAccountClosed, err.Error())
+	case errors.Is(err, services.ErrAlreadyFrozen):
+		return respondError(c, http.StatusConflict, types.CodeAlreadyFrozen, err.Error())
+	case errors.Is(err, services.ErrNotFrozen):
+		return respondError(c, http.StatusConflict, types.CodeNotFrozen, err.Error())
+	case err != nil:
+		return err
+	}
+
+	setETag(c, account.Version)
+	return c.JSON(http.StatusOK, account)
+}
@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/labstack/echo/v4"
+
+	"BankSystemGoLang/graph"
+	"BankSystemGoLang/middleware"
+	"BankSystemGoLang/types"
+)
+
+type GraphQLHandler struct {
+	schema *graphql.Schema
+}
+
+func NewGraphQLHandler(schema *graphql.Schema) *GraphQLHandler {
+	return &GraphQLHandler{schema: schema}
+}
+
+// Serve handles POST /graphql for the authenticated caller. As is usual
+// for GraphQL, a query that runs is answered with 200 even when some of
+// its fields fail; their errors are listed in the body with the REST error
+// code in extensions.code.
+func (h *GraphQLHandler) Serve(c echo.Context) error {
+	var req types.GraphQLRequest
+	if err := c.Bind(&req); err != nil {
+		return bindError(c, err)
+	}
+
+	ctx := graph.WithCaller(c.Request().Context(), graph.Caller{
+		UserID:    middleware.UserID(c),
+		RequestID: middleware.RequestIDFrom(c),
+	})
+	return c.JSON(http.StatusOK, h.schema.Exec(ctx, req.Query, req.OperationName, req.Variables))
+}
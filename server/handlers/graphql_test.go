@@ -0,0 +1,122 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"BankSystemGoLang/types"
+)
+
+// graphQLResponse is the body /graphql answers with.
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string   `json:"message"`
+		Path       []string `json:"path"`
+		Extensions struct {
+			Code string `json:"code"`
+		} `json:"extensions"`
+	} `json:"errors"`
+}
+
+func (s *testServer) graphQL(t *testing.T, token, query string) graphQLResponse {
+	t.Helper()
+	body, err := json.Marshal(types.GraphQLRequest{Query: query})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := s.do(token, http.MethodPost, "/graphql", string(body))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("POST /graphql = %d %s", rec.Code, rec.Body)
+	}
+	var res graphQLResponse
+	decode(t, rec, &res)
+	return res
+}
+
+func TestGraphQLAccount(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.login(t, "alice@example.com")
+	bob := s.login(t, "bob@example.com")
+	s.openAccount(t, alice, `{"owner_name":"Alice","email":"alice@example.com","initial_balance":"42.50"}`)
+
+	const query = `{ account(id: "1") { id ownerName email balance available status frozen } }`
+	res := s.graphQL(t, alice, query)
+	if len(res.Errors) != 0 {
+		t.Fatalf("errors = %+v", res.Errors)
+	}
+	var data struct {
+		Account struct {
+			ID        string `json:"id"`
+			OwnerName string `json:"ownerName"`
+			Email     string `json:"email"`
+			Balance   string `json:"balance"`
+			Available string `json:"available"`
+			Status    string `json:"status"`
+			Frozen    bool   `json:"frozen"`
+		} `json:"account"`
+	}
+	if err := json.Unmarshal(res.Data, &data); err != nil {
+		t.Fatal(err)
+	}
+	got := data.Account
+	if got.ID != "1" || got.OwnerName != "Alice" || got.Email != "alice@example.com" ||
+		got.Balance != "42.50" || got.Available != "42.50" || got.Status != string(types.AccountOpen) || got.Frozen {
+		t.Errorf("account = %+v, want Alice's open account holding 42.50", got)
+	}
+
+	res = s.graphQL(t, bob, query)
+	if len(res.Errors) != 1 || res.Errors[0].Extensions.Code != types.CodeForbidden {
+		t.Fatalf("errors = %+v, want one %s", res.Errors, types.CodeForbidden)
+	}
+	if string(res.Data) != "null" {
+		t.Errorf("data = %s, want null for another user's account", res.Data)
+	}
+
+	res = s.graphQL(t, alice, `{ account(id: "99") { id } }`)
+	if len(res.Errors) != 1 || res.Errors[0].Extensions.Code != types.CodeAccountNotFound {
+		t.Errorf("errors = %+v, want one %s", res.Errors, types.CodeAccountNotFound)
+	}
+}
+
+func TestGraphQLTransferHidesDestination(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.login(t, "alice@example.com")
+	bob := s.login(t, "bob@example.com")
+	s.openAccount(t, alice, `{"owner_name":"Alice","email":"alice@example.com","initial_balance":"100.00"}`)
+	s.openAccount(t, alice, `{"owner_name":"Alice","email":"alice.savings@example.com"}`)
+	s.openAccount(t, bob, `{"owner_name":"Bob","email":"bob@example.com","initial_balance":"500.00"}`)
+
+	tests := []struct {
+		name   string
+		toID   string
+		wantTo bool
+	}{
+		{"own account", "2", true},
+		{"another user's account", "3", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := s.graphQL(t, alice, `mutation { transfer(fromId: "1", toId: "`+tt.toID+`", amount: "10.00") { toId to { id ownerName balance } } }`)
+			if len(res.Errors) != 0 {
+				t.Fatalf("errors = %+v", res.Errors)
+			}
+			var data struct {
+				Transfer struct {
+					ToID string           `json:"toId"`
+					To   *json.RawMessage `json:"to"`
+				} `json:"transfer"`
+			}
+			if err := json.Unmarshal(res.Data, &data); err != nil {
+				t.Fatal(err)
+			}
+			if data.Transfer.ToID != tt.toID {
+				t.Errorf("toId = %q, want %q", data.Transfer.ToID, tt.toID)
+			}
+			if got := data.Transfer.To != nil; got != tt.wantTo {
+				t.Errorf("to present = %v, want %v: %s", got, tt.wantTo, res.Data)
+			}
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"BankSystemGoLang/clock"
+	"BankSystemGoLang/graph"
+	"BankSystemGoLang/handlers"
+	"BankSystemGoLang/metrics"
+	"BankSystemGoLang/middleware"
+	"BankSystemGoLang/route"
+	"BankSystemGoLang/services"
+	"BankSystemGoLang/store"
+	"BankSystemGoLang/types"
+)
+
+const (
+	testSecret     = "handlers-test-secret-of-32-bytes"
+	testAdminEmail = "admin@example.com"
+	testPassword   = "correct horse battery"
+)
+
+// testServer is the full API, wired as main does, over a MemoryStore.
+type testServer struct {
+	e        *echo.Echo
+	store    store.Store
+	accounts *services.AccountService
+	auth     *services.AuthService
+	audit    *services.AuditService
+	clock    *clock.Mock
+	mail     *outbox
+}
+
+// outbox is a Mailer keeping every message it is asked to send, or failing
+// them all while err is set.
+type outbox struct {
+	mu       sync.Mutex
+	messages []message
+	err      error
+}
+
+type message struct{ to, subject, body string }
+
+func (o *outbox) Send(to, subject, body string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.err != nil {
+		return o.err
+	}
+	o.messages = append(o.messages, message{to, subject, body})
+	return nil
+}
+
+func (o *outbox) sent() []message {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return slices.Clone(o.messages)
+}
+
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+	return newTestServerWith(t, store.NewMemoryStore(), services.StaticRates{})
+}
+
+func newTestServerWith(t *testing.T, st store.Store, rates services.RateProvider) *testServer {
+	t.Helper()
+	log := zap.NewNop()
+	clk := clock.NewMock(time.Date(2025, time.March, 10, 12, 0, 0, 0, time.UTC))
+
+	accounts := services.NewAccountService(st, testSecret, big.NewRat(2, 100), rates, clk)
+	auth := services.NewAuthService(st, testSecret, []string{testAdminEmail}, clk)
+	audit := services.NewAuditService(st, clk)
+	mail := &outbox{}
+	verification := services.NewVerificationService(st, mail, "http://bank.test/verify", clk)
+	schedules := services.NewScheduleService(st, accounts, clk)
+	m := metrics.New(accounts.Count)
+	accounts.Subscribe(m.Observe)
+
+	schema, err := graph.NewSchema(accounts, log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	spec, err := route.OpenAPI()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e := echo.New()
+	e.HTTPErrorHandler = handlers.NewHTTPErrorHandler(log)
+	e.Binder = handlers.NewBinder()
+	e.Use(middleware.RequestID())
+	e.Use(m.Middleware())
+	route.Register(e, route.Handlers{
+		Health:    handlers.NewHealthHandler(st, log),
+		Metrics:   m.Handler(),
+		Auth:      handlers.NewAuthHandler(auth, verification, log),
+		Accounts:  handlers.NewAccountHandler(accounts, clk),
+		Holds:     handlers.NewHoldHandler(accounts),
+		Transfers: handlers.NewTransferHandler(accounts),
+		Schedules: handlers.NewScheduleHandler(accounts, schedules),
+		Webhooks:  handlers.NewWebhookHandler(services.NewWebhookService(st, accounts, clk)),
+		Audit:     handlers.NewAuditHandler(audit, clk),
+		GraphQL:   handlers.NewGraphQLHandler(schema),
+		Docs:      handlers.NewDocsHandler(spec, route.DocsPage()),
+	}, route.Middleware{
+		RequireAuth:  middleware.RequireAuth(auth),
+		RequireAdmin: middleware.RequireRole(types.RoleAdmin),
+		Idempotent:   middleware.Idempotent(middleware.NewIdempotencyCache(middleware.IdempotencyTTL, clk)),
+		RateLimit:    middleware.RateLimit(middleware.NewRateLimiter(1_000_000, clk)),
+		Audit:        middleware.Audit(audit, log),
+	})
+
+	return &testServer{e: e, store: st, accounts: accounts, auth: auth, audit: audit, clock: clk, mail: mail}
+}
+
+// login registers a verified user with the given email and returns a
+// bearer token for it. testAdminEmail gets the admin role.
+func (s *testServer) login(t *testing.T, email string) string {
+	t.Helper()
+	ctx := context.Background()
+	user, err := s.auth.Register(ctx, types.RegisterRequest{Email: email, Password: testPassword})
+	if err != nil {
+		t.Fatalf("register %s: %v", email, err)
+	}
+	if err := s.store.MarkUserVerified(ctx, user.ID, s.clock.Now()); err != nil {
+		t.Fatal(err)
+	}
+	return s.token(t, email)
+}
+
+// token logs in as a user registered by login and returns a fresh bearer
+// token, for tests that move the clock past the previous one's expiry.
+func (s *testServer) token(t *testing.T, email string) string {
+	t.Helper()
+	resp, err := s.auth.Login(context.Background(), types.LoginRequest{Email: email, Password: testPassword})
+	if err != nil {
+		t.Fatalf("login %s: %v", email, err)
+	}
+	return resp.Token
+}
+
+// do sends a request with a JSON body, authenticated by token unless it is
+// empty, and extra headers given as name, value pairs.
+func (s *testServer) do(token, method, path, body string, headers ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	}
+	if token != "" {
+		req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+	}
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	rec := httptest.NewRecorder()
+	s.e.ServeHTTP(rec, req)
+	return rec
+}
+
+// openAccount creates an account through the API and returns it.
+func (s *testServer) openAccount(t *testing.T, token, body string) types.Account {
+	t.Helper()
+	rec := s.do(token, http.MethodPost, "/accounts", body)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("POST /accounts = %d %s", rec.Code, rec.Body)
+	}
+	var account types.Account
+	decode(t, rec, &account)
+	return account
+}
+
+// balance returns the account's current balance as stored.
+func (s *testServer) balance(t *testing.T, id int64) types.Money {
+	t.Helper()
+	account, err := s.store.GetAccount(context.Background(), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return account.Balance
+}
+
+func decode(t *testing.T, rec *httptest.ResponseRecorder, v any) {
+	t.Helper()
+	if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+		t.Fatalf("decode %s: %v", rec.Body, err)
+	}
+}
+
+// expectError checks the response status and the code of its error body.
+func expectError(t *testing.T, rec *httptest.ResponseRecorder, status int, code string) types.ErrorResponse {
+	t.Helper()
+	if rec.Code != status {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, status, rec.Body)
+	}
+	var body types.ErrorResponse
+	decode(t, rec, &body)
+	if body.Code != code {
+		t.Fatalf("code = %q, want %q", body.Code, code)
+	}
+	return body
+}
@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"BankSystemGoLang/types"
+)
+
+// HealthTimeout bounds the database ping so a stuck database makes the
+// check fail fast instead of hanging the load balancer's probe.
+const HealthTimeout = time.Second
+
+// Pinger is implemented by stores that can check their backing database.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+type HealthHandler struct {
+	db  Pinger
+	log *zap.Logger
+}
+
+func NewHealthHandler(db Pinger, log *zap.Logger) *HealthHandler {
+	return &HealthHandler{db: db, log: log}
+}
+
+// Check handles GET /health.
+func (h *HealthHandler) Check(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), HealthTimeout)
+	defer cancel()
+
+	if err := h.db.Ping(ctx); err != nil {
+		h.log.Warn("database ping failed", zap.Error(err))
+		return c.JSON(http.StatusServiceUnavailable, types.HealthResponse{Status: types.HealthUnhealthy, DB: "down"})
+	}
+	return c.JSON(http.StatusOK, types.HealthResponse{Status: types.HealthOK})
+}
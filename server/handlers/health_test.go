@@ -0,0 +1,45 @@
+package handlers_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"BankSystemGoLang/handlers"
+)
+
+type fakePinger struct{ err error }
+
+func (p fakePinger) Ping(context.Context) error { return p.err }
+
+func TestHealthCheck(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		want   string
+	}{
+		{name: "healthy", status: http.StatusOK, want: `{"status":"ok"}`},
+		{name: "database down", err: errors.New("connection refused"), status: http.StatusServiceUnavailable, want: `{"status":"unhealthy","db":"down"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			e.GET("/health", handlers.NewHealthHandler(fakePinger{tt.err}, zap.NewNop()).Check)
+
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d", rec.Code, tt.status)
+			}
+			if got := rec.Body.String(); got != tt.want+"\n" {
+				t.Errorf("body = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
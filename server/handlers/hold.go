@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"BankSystemGoLang/services"
+	"BankSystemGoLang/types"
+)
+
+type HoldHandler struct {
+	accounts *services.AccountService
+}
+
+func NewHoldHandler(accounts *services.AccountService) *HoldHandler {
+	return &HoldHandler{accounts: accounts}
+}
+
+// Create handles POST /accounts/:id/holds.
+func (h *HoldHandler) Create(c echo.Context) error {
+	id, err := parseID(c.Param("id"))
+	if err != nil {
+		return respondError(c, http.StatusBadRequest, types.CodeInvalidAccountID, "invalid account id")
+	}
+
+	var req types.HoldRequest
+	if err := c.Bind(&req); err != nil {
+		return bindError(c, err)
+	}
+
+	if _, err := ownAccount(c, h.accounts, id); err != nil {
+		return accessError(c, err)
+	}
+
+	hold, account, err := h.accounts.PlaceHold(c.Request().Context(), id, req.Amount)
+	var limitErr *services.DailyLimitError
+	switch {
+	case errors.Is(err, services.ErrInvalidAmount):
+		return respondError(c, http.StatusBadRequest, types.CodeInvalidAmount, err.Error())
+	case errors.Is(err, services.ErrAccountNotFound):
+		return respondError(c, http.StatusNotFound, types.CodeAccountNotFound, err.Error())
+	case errors.Is(err, services.ErrAccountClosed):
+		return respondError(c, http.StatusConflict, types.CodeAccountClosed, err.Error())
+	case errors.Is(err, services.ErrAccountFrozen):
+		return respondError(c, http.StatusLocked, types.CodeAccountFrozen, err.Error())
+	case errors.Is(err, services.ErrInsufficientFunds):
+		return respondInsufficientFunds(c, err.Error(), account.Balance)
+	case errors.As(err, &limitErr):
+		return respondDailyLimitExceeded(c, limitErr)
+	case err != nil:
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, hold)
+}
+
+// Capture handles POST /holds/:id/capture.
+func (h *HoldHandler) Capture(c echo.Context) error {
+	return h.resolve(c, h.accounts.CaptureHold)
+}
+
+// Release handles POST /holds/:id/release.
+func (h *HoldHandler) Release(c echo.Context) error {
+	return h.resolve(c, h.accounts.ReleaseHold)
+}
+
+func (h *HoldHandler) resolve(c echo.Context, resolve func(context.Context, int64) (types.Hold, types.Account, error)) error {
+	id, err := parseID(c.Param("id"))
+	if err != nil {
+		return respondError(c, http.StatusBadRequest, types.CodeInvalidHoldID, "invalid hold id")
+	}
+
+	hold, err := h.accounts.GetHold(c.Request().Context(), id)
+	if errors.Is(err, services.ErrHoldNotFound) {
+		return respondError(c, http.StatusNotFound, types.CodeHoldNotFound, err.Error())
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := ownAccount(c, h.accounts, hold.AccountID); err != nil {
+		return accessError(c, err)
+	}
+
+	hold, _, err = resolve(c.Request().Context(), id)
+	var limitErr *services.DailyLimitError
+	switch {
+	case errors.Is(err, services.ErrHoldNotFound):
+		return respondError(c, http.StatusNotFound, types.CodeHoldNotFound, err.Error())
+	case errors.Is(err, services.ErrHoldNotActive):
+		return respondError(c, http.StatusConflict, types.CodeHoldNotActive, err.Error())
+	case errors.Is(err, services.ErrAccountFrozen):
+		return respondError(c, http.StatusLocked, types.CodeAccountFrozen, err.Error())
+	case errors.As(err, &limitErr):
+		return respondDailyLimitExceeded(c, limitErr)
+	case err != nil:
+		return err
+	}
+
+	return c.JSON(http.StatusOK, hold)
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"BankSystemGoLang/middleware"
+	"BankSystemGoLang/services"
+	"BankSystemGoLang/types"
+)
+
+type ScheduleHandler struct {
+	accounts  *services.AccountService
+	schedules *services.ScheduleService
+}
+
+func NewScheduleHandler(accounts *services.AccountService, schedules *services.ScheduleService) *ScheduleHandler {
+	return &ScheduleHandler{accounts: accounts, schedules: schedules}
+}
+
+// Create handles POST /scheduled-transfers.
+func (h *ScheduleHandler) Create(c echo.Context) error {
+	var req types.ScheduledTransferRequest
+	if err := c.Bind(&req); err != nil {
+		return bindError(c, err)
+	}
+
+	if _, err := ownAccount(c, h.accounts, req.FromID); err != nil {
+		return accessError(c, err)
+	}
+
+	st, err := h.schedules.Create(c.Request().Context(), middleware.UserID(c), req)
+	switch {
+	case errors.Is(err, services.ErrSameAccount):
+		return respondError(c, http.StatusBadRequest, types.CodeSameAccount, err.Error())
+	case errors.Is(err, services.ErrInvalidAmount):
+		return respondError(c, http.StatusBadRequest, types.CodeInvalidAmount, err.Error())
+	case errors.Is(err, services.ErrInvalidFrequency):
+		return respondError(c, http.StatusBadRequest, types.CodeInvalidFrequency, err.Error())
+	case errors.Is(err, services.ErrAccountNotFound):
+		return respondError(c, http.StatusNotFound, types.CodeAccountNotFound, err.Error())
+	case errors.Is(err, services.ErrAccountClosed):
+		return respondError(c, http.StatusConflict, types.CodeAccountClosed, err.Error())
+	case err != nil:
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, st)
+}
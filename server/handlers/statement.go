@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/labstack/echo/v4"
+
+	"BankSystemGoLang/services"
+	"BankSystemGoLang/types"
+)
+
+// dateLayout is the format of the from and to statement parameters.
+const dateLayout = time.DateOnly
+
+// Statement handles GET /accounts/:id/statement. from and to are inclusive
+// YYYY-MM-DD dates defaulting to the last 30 days; format is csv (the
+// default) or pdf.
+func (h *AccountHandler) Statement(c echo.Context) error {
+	id, err := parseID(c.Param("id"))
+	if err != nil {
+		return respondError(c, http.StatusBadRequest, types.CodeInvalidAccountID, "invalid account id")
+	}
+
+	to := h.clock.Now().UTC()
+	from := to.AddDate(0, 0, 1-services.DefaultStatementDays)
+	if err := echo.QueryParamsBinder(c).
+		Time("from", &from, dateLayout).
+		Time("to", &to, dateLayout).
+		BindError(); err != nil {
+		return respondError(c, http.StatusBadRequest, types.CodeInvalidDate, "from and to must be dates formatted as YYYY-MM-DD")
+	}
+
+	format := c.QueryParam("format")
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "pdf" {
+		return respondError(c, http.StatusBadRequest, types.CodeInvalidFormat, "format must be csv or pdf")
+	}
+
+	if _, err := ownAccount(c, h.accounts, id); err != nil {
+		return accessError(c, err)
+	}
+
+	statement, err := h.accounts.Statement(c.Request().Context(), id, from, to)
+	switch {
+	case errors.Is(err, services.ErrInvalidDateRange):
+		return respondError(c, http.StatusBadRequest, types.CodeInvalidDateRange, err.Error())
+	case errors.Is(err, services.ErrAccountNotFound):
+		return respondError(c, http.StatusNotFound, types.CodeAccountNotFound, err.Error())
+	case err != nil:
+		return err
+	}
+
+	if format == "pdf" {
+		return writePDFStatement(c, statement)
+	}
+	return writeCSVStatement(c, statement)
+}
+
+func writeCSVStatement(c echo.Context, s types.Statement) error {
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/csv; charset=utf-8")
+	res.Header().Set(echo.HeaderContentDisposition, "attachment; filename="+statementFilename(s, "csv"))
+	res.WriteHeader(http.StatusOK)
+
+	w := csv.NewWriter(res)
+	if err := w.Write([]string{"date", "type", "amount", "balance_after"}); err != nil {
+		return err
+	}
+	for _, t := range s.Transactions {
+		record := []string{
+			t.CreatedAt.UTC().Format(time.RFC3339),
+			string(t.Type),
+			t.Amount.String(),
+			t.BalanceAfter.String(),
+		}
+		if err := w.Write(record); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// writePDFStatement renders the statement as a one-table PDF. fpdf builds
+// the document in memory, so it is only written once complete, straight
+// into the response without another copy.
+func writePDFStatement(c echo.Context, s types.Statement) error {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	tr := pdf.UnicodeTranslatorFromDescriptor("") // UTF-8 to the core fonts' cp1252
+	pdf.SetTitle("Account statement", true)
+	pdf.AddPage()
+
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.CellFormat(0, 10, "Account statement", "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 11)
+	pdf.CellFormat(0, 6, tr("Account holder: "+s.Account.OwnerName), "", 1, "L", false, 0, "")
+	pdf.CellFormat(0, 6, fmt.Sprintf("Account: %d", s.Account.ID), "", 1, "L", false, 0, "")
+	pdf.CellFormat(0, 6, "Period: "+s.From.Format(dateLayout)+" to "+s.To.Format(dateLayout), "", 1, "L", false, 0, "")
+	pdf.Ln(4)
+
+	widths := []float64{60, 40, 40, 40}
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.SetFillColor(230, 230, 230)
+	for i, title := range []string{"Date", "Type", "Amount", "Balance after"} {
+		pdf.CellFormat(widths[i], 7, title, "1", 0, "L", true, 0, "")
+	}
+	pdf.Ln(-1)
+
+	pdf.SetFont("Helvetica", "", 10)
+	for _, t := range s.Transactions {
+		pdf.CellFormat(widths[0], 6, t.CreatedAt.UTC().Format("2006-01-02 15:04:05"), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(widths[1], 6, string(t.Type), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(widths[2], 6, t.Amount.String(), "1", 0, "R", false, 0, "")
+		pdf.CellFormat(widths[3], 6, t.BalanceAfter.String(), "1", 0, "R", false, 0, "")
+		pdf.Ln(-1)
+	}
+	if len(s.Transactions) == 0 {
+		pdf.CellFormat(0, 6, "No transactions in this period.", "1", 1, "L", false, 0, "")
+	}
+
+	if err := pdf.Error(); err != nil {
+		return err
+	}
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "application/pdf")
+	res.Header().Set(echo.HeaderContentDisposition, "attachment; filename="+statementFilename(s, "pdf"))
+	res.WriteHeader(http.StatusOK)
+	return pdf.Output(res)
+}
+
+func statementFilename(s types.Statement, ext string) string {
+	return fmt.Sprintf("statement-%d-%s-%s.%s", s.Account.ID, s.From.Format(dateLayout), s.To.Format(dateLayout), ext)
+}
@@ -0,0 +1,133 @@
+package handlers_test
+
+import (
+	"encoding/csv"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"BankSystemGoLang/services"
+	"BankSystemGoLang/types"
+)
+
+// statementServer returns a server with one account of the caller's
+// holding an opening balance of 100.00, a deposit and a withdrawal.
+func statementServer(t *testing.T) (*testServer, string) {
+	t.Helper()
+	s := newTestServer(t)
+	token := s.login(t, "alice@example.com")
+	s.openAccount(t, token, `{"owner_name":"Alice","email":"alice@example.com","initial_balance":"100.00"}`)
+	for _, req := range []struct{ path, body string }{
+		{"/accounts/1/deposit", `{"amount":"25.00"}`},
+		{"/accounts/1/withdraw", `{"amount":"10.50"}`},
+	} {
+		if rec := s.do(token, http.MethodPost, req.path, req.body); rec.Code != http.StatusOK {
+			t.Fatalf("POST %s = %d %s", req.path, rec.Code, rec.Body)
+		}
+	}
+	return s, token
+}
+
+func TestStatementCSV(t *testing.T) {
+	s, token := statementServer(t)
+	today := s.clock.Now().UTC().Format(time.DateOnly)
+
+	rec := s.do(token, http.MethodGet, "/accounts/1/statement?from="+today+"&to="+today, "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d %s", rec.Code, rec.Body)
+	}
+	if ct := rec.Header().Get(echo.HeaderContentType); !strings.HasPrefix(ct, "text/csv") {
+		t.Errorf("Content-Type = %q, want text/csv", ct)
+	}
+	if cd := rec.Header().Get(echo.HeaderContentDisposition); !strings.Contains(cd, "statement-1-"+today+"-"+today+".csv") {
+		t.Errorf("Content-Disposition = %q, want the statement filename", cd)
+	}
+
+	rows, err := csv.NewReader(rec.Body).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		{"date", "type", "amount", "balance_after"},
+		{"", string(types.TransactionOpening), "100.00", "100.00"},
+		{"", string(types.TransactionDeposit), "25.00", "125.00"},
+		{"", string(types.TransactionWithdrawal), "10.50", "114.50"},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("got %d rows, want %d: %v", len(rows), len(want), rows)
+	}
+	for i := range want {
+		if i > 0 {
+			if _, err := time.Parse(time.RFC3339, rows[i][0]); err != nil {
+				t.Errorf("row %d date %q is not RFC 3339", i, rows[i][0])
+			}
+			want[i][0] = rows[i][0]
+		}
+		if strings.Join(rows[i], ",") != strings.Join(want[i], ",") {
+			t.Errorf("row %d = %v, want %v", i, rows[i], want[i])
+		}
+	}
+}
+
+func TestStatementDateErrors(t *testing.T) {
+	s, token := statementServer(t)
+
+	rec := s.do(token, http.MethodGet, "/accounts/1/statement?from=2025-03-10&to=2025-03-01", "")
+	expectError(t, rec, http.StatusBadRequest, types.CodeInvalidDateRange)
+
+	rec = s.do(token, http.MethodGet, "/accounts/1/statement?from=10/03/2025", "")
+	expectError(t, rec, http.StatusBadRequest, types.CodeInvalidDate)
+}
+
+func TestStatementPDF(t *testing.T) {
+	s, token := statementServer(t)
+
+	rec := s.do(token, http.MethodGet, "/accounts/1/statement?format=pdf", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d %s", rec.Code, rec.Body)
+	}
+	if ct := rec.Header().Get(echo.HeaderContentType); ct != "application/pdf" {
+		t.Errorf("Content-Type = %q, want application/pdf", ct)
+	}
+	if !strings.HasPrefix(rec.Body.String(), "%PDF-") {
+		t.Errorf("body starts %q, want the %%PDF- magic bytes", rec.Body.String()[:min(rec.Body.Len(), 8)])
+	}
+
+	rec = s.do(token, http.MethodGet, "/accounts/1/statement?format=xlsx", "")
+	expectError(t, rec, http.StatusBadRequest, types.CodeInvalidFormat)
+}
+
+func TestStatementDefaultWindowFollowsClock(t *testing.T) {
+	s, token := statementServer(t)
+
+	rows := func() [][]string {
+		t.Helper()
+		rec := s.do(token, http.MethodGet, "/accounts/1/statement", "")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d %s", rec.Code, rec.Body)
+		}
+		today := s.clock.Now().UTC()
+		from := today.AddDate(0, 0, 1-services.DefaultStatementDays).Format(time.DateOnly)
+		if cd := rec.Header().Get(echo.HeaderContentDisposition); !strings.Contains(cd, from+"-"+today.Format(time.DateOnly)) {
+			t.Errorf("Content-Disposition = %q, want the %d days up to the clock's today", cd, services.DefaultStatementDays)
+		}
+		rows, err := csv.NewReader(rec.Body).ReadAll()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return rows
+	}
+
+	if got := rows(); len(got) != 4 {
+		t.Errorf("statement on the day = %d rows, want the header and 3 entries", len(got))
+	}
+	// The old token has expired by then too.
+	s.clock.Advance(services.DefaultStatementDays * 24 * time.Hour)
+	token = s.token(t, "alice@example.com")
+	if got := rows(); len(got) != 1 {
+		t.Errorf("statement %d days later = %v, want only the header", services.DefaultStatementDays, got)
+	}
+}
@@ -0,0 +1,86 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"BankSystemGoLang/middleware"
+	"BankSystemGoLang/services"
+	"BankSystemGoLang/store"
+	"BankSystemGoLang/types"
+)
+
+// stuckStore is a MemoryStore whose account reads hang until the caller's
+// context ends once stuck is set, like a database that stopped answering.
+type stuckStore struct {
+	*store.MemoryStore
+	stuck atomic.Bool
+}
+
+func (s *stuckStore) GetAccount(ctx context.Context, id int64) (types.Account, error) {
+	if s.stuck.Load() {
+		<-ctx.Done()
+		return types.Account{}, ctx.Err()
+	}
+	return s.MemoryStore.GetAccount(ctx, id)
+}
+
+func TestRequestTimeout(t *testing.T) {
+	st := &stuckStore{MemoryStore: store.NewMemoryStore()}
+	s := newTestServerWith(t, st, services.StaticRates{})
+	s.e.Use(middleware.Timeout(50 * time.Millisecond))
+	token := s.login(t, "alice@example.com")
+	s.openAccount(t, token, `{"owner_name":"Alice","email":"alice@example.com","initial_balance":"10.00"}`)
+
+	st.stuck.Store(true)
+	start := time.Now()
+	rec := s.do(token, http.MethodPost, "/accounts/1/deposit", `{"amount":"5.00"}`)
+	expectError(t, rec, http.StatusGatewayTimeout, types.CodeTimeout)
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("request took %s, want it cut off near the 50ms timeout", elapsed)
+	}
+
+	st.stuck.Store(false)
+	if got := s.balance(t, 1); got != 10_00 {
+		t.Errorf("balance = %s, want 10.00", got)
+	}
+}
+
+func TestCancelledRequestReleasesIdempotencyKey(t *testing.T) {
+	st := &stuckStore{MemoryStore: store.NewMemoryStore()}
+	s := newTestServerWith(t, st, services.StaticRates{})
+	token := s.login(t, "alice@example.com")
+	s.openAccount(t, token, `{"owner_name":"Alice","email":"alice@example.com","initial_balance":"10.00"}`)
+
+	// The client gives up while the deposit waits on the store.
+	st.stuck.Store(true)
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest(http.MethodPost, "/accounts/1/deposit", strings.NewReader(`{"amount":"5.00"}`)).WithContext(ctx)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+	req.Header.Set(middleware.HeaderIdempotencyKey, "deposit-1")
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+	}()
+	s.e.ServeHTTP(httptest.NewRecorder(), req)
+
+	st.stuck.Store(false)
+	rec := s.do(token, http.MethodPost, "/accounts/1/deposit", `{"amount":"5.00"}`, middleware.HeaderIdempotencyKey, "deposit-1")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("retry = %d %s, want the deposit made", rec.Code, rec.Body)
+	}
+	if replayed := rec.Header().Get(middleware.HeaderIdempotentReplayed); replayed != "" {
+		t.Errorf("%s = %q, want the retry run rather than replayed", middleware.HeaderIdempotentReplayed, replayed)
+	}
+	if got := s.balance(t, 1); got != 15_00 {
+		t.Errorf("balance = %s, want 15.00", got)
+	}
+}
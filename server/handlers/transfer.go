@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"BankSystemGoLang/middleware"
+	"BankSystemGoLang/services"
+	"BankSystemGoLang/types"
+)
+
+type TransferHandler struct {
+	accounts *services.AccountService
+}
+
+func NewTransferHandler(accounts *services.AccountService) *TransferHandler {
+	return &TransferHandler{accounts: accounts}
+}
+
+// Create handles POST /transfers. A dry run answers as the transfer would,
+// failures included, without moving any money. The destination's balance is
+// left out unless it is the caller's own account.
+func (h *TransferHandler) Create(c echo.Context) error {
+	dryRun, err := middleware.DryRun(c)
+	if err != nil {
+		return respondError(c, http.StatusBadRequest, types.CodeBadRequest, err.Error())
+	}
+	var req types.TransferRequest
+	if err := c.Bind(&req); err != nil {
+		return bindError(c, err)
+	}
+
+	if _, err := ownAccount(c, h.accounts, req.FromID); err != nil {
+		return accessError(c, err)
+	}
+
+	transfer := h.accounts.Transfer
+	if dryRun {
+		transfer = h.accounts.PreviewTransfer
+	}
+	from, to, fx, err := transfer(c.Request().Context(), req.FromID, req.ToID, req.Amount)
+	if errors.Is(err, services.ErrInsufficientFunds) {
+		return respondInsufficientFunds(c, err.Error(), from.Balance)
+	}
+	if status, code, message, ok := transferFailure(err); ok {
+		return respondError(c, status, code, message)
+	}
+	if err != nil {
+		return err
+	}
+
+	res := types.TransferResponse{
+		FromID:      from.ID,
+		ToID:        to.ID,
+		Amount:      req.Amount,
+		FromBalance: from.Balance,
+		DryRun:      dryRun,
+	}
+	if to.UserID == from.UserID {
+		res.ToBalance = &to.Balance
+	}
+	if fx != nil {
+		res.ConvertedAmount, res.ExchangeRate = &fx.Credited, fx.Rate
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// Batch handles POST /transfers/batch. Every source account must belong to
+// the caller. A failed batch is answered with the status the failing
+// transfer would have got on its own and the per-item results.
+func (h *TransferHandler) Batch(c echo.Context) error {
+	var req types.BatchTransferRequest
+	if err := c.Bind(&req); err != nil {
+		return bindError(c, err)
+	}
+
+	for _, item := range req.Transfers {
+		if _, err := ownAccount(c, h.accounts, item.FromID); err != nil {
+			return accessError(c, err)
+		}
+	}
+
+	outcomes, err := h.accounts.TransferBatch(c.Request().Context(), req.Transfers)
+	switch {
+	case errors.Is(err, services.ErrEmptyBatch):
+		return respondError(c, http.StatusBadRequest, types.CodeBadRequest, err.Error())
+	case errors.Is(err, services.ErrBatchTooLarge):
+		return respondError(c, http.StatusBadRequest, types.CodeBatchTooLarge, err.Error())
+	case err != nil && !errors.Is(err, services.ErrBatchFailed):
+		return err
+	}
+
+	results := make([]types.BatchTransferResult, len(outcomes))
+	status := http.StatusUnprocessableEntity
+	for i, o := range outcomes {
+		item := req.Transfers[i]
+		results[i] = types.BatchTransferResult{
+			Index:  i,
+			FromID: item.FromID,
+			ToID:   item.ToID,
+			Amount: item.Amount,
+			Status: o.Status,
+		}
+		if o.FX != nil {
+			results[i].ConvertedAmount, results[i].ExchangeRate = &o.FX.Credited, o.FX.Rate
+		}
+		if o.Err == nil {
+			continue
+		}
+		itemStatus, code, message, ok := transferFailure(o.Err)
+		if !ok {
+			return o.Err
+		}
+		status, results[i].Code, results[i].Error = itemStatus, code, message
+	}
+
+	if err != nil {
+		body := errorResponse(c, types.CodeBatchFailed, services.ErrBatchFailed.Error())
+		body.Results = results
+		return c.JSON(status, body)
+	}
+	return c.JSON(http.StatusOK, types.BatchTransferResponse{Results: results})
+}
+
+// transferFailure maps the expected ways a transfer fails to a response;
+// ok is false for any other error.
+func transferFailure(err error) (status int, code, message string, ok bool) {
+	switch {
+	case errors.Is(err, services.ErrSameAccount):
+		return http.StatusBadRequest, types.CodeSameAccount, err.Error(), true
+	case errors.Is(err, services.ErrInvalidAmount):
+		return http.StatusBadRequest, types.CodeInvalidAmount, err.Error(), true
+	case errors.Is(err, services.ErrAccountNotFound):
+		return http.StatusNotFound, types.CodeAccountNotFound, err.Error(), true
+	case errors.Is(err, services.ErrAccountClosed):
+		return http.StatusConflict, types.CodeAccountClosed, err.Error(), true
+	case errors.Is(err, services.ErrAccountFrozen):
+		return http.StatusLocked, types.CodeAccountFrozen, err.Error(), true
+	case errors.Is(err, services.ErrInsufficientFunds):
+		return http.StatusUnprocessableEntity, types.CodeInsufficientFunds, err.Error(), true
+	case errors.Is(err, services.ErrExchangeRate):
+		// The provider's error may describe internals; it is not passed on.
+		return http.StatusBadGateway, types.CodeExchangeRate, services.ErrExchangeRate.Error(), true
+	}
+	return 0, "", "", false
+}
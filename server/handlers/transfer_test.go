@@ -0,0 +1,166 @@
+package handlers_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http"
+	"strings"
+	"testing"
+
+	"BankSystemGoLang/middleware"
+	"BankSystemGoLang/services"
+	"BankSystemGoLang/store"
+	"BankSystemGoLang/types"
+)
+
+// failingRates is a RateProvider whose upstream is down.
+type failingRates struct{}
+
+func (failingRates) Rate(string, string) (*big.Rat, error) {
+	return nil, errors.New("rates service unavailable")
+}
+
+func TestTransferExchangeRateUnavailable(t *testing.T) {
+	s := newTestServerWith(t, store.NewMemoryStore(), failingRates{})
+	token := s.login(t, "alice@example.com")
+	eur := s.openAccount(t, token, `{"owner_name":"Alice","email":"alice@example.com","currency":"EUR","initial_balance":"50.00"}`)
+	usd := s.openAccount(t, token, `{"owner_name":"Alice","email":"alice+usd@example.com","currency":"USD"}`)
+
+	rec := s.do(token, http.MethodPost, "/transfers", `{"from_id":1,"to_id":2,"amount":"20.00"}`)
+	expectError(t, rec, http.StatusBadGateway, types.CodeExchangeRate)
+	if got := s.balance(t, eur.ID); got != 50_00 {
+		t.Errorf("source balance = %s, want 50.00", got)
+	}
+	if got := s.balance(t, usd.ID); got != 0 {
+		t.Errorf("destination balance = %s, want 0.00", got)
+	}
+}
+
+func TestTransferBatchIsAllOrNothing(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login(t, "alice@example.com")
+	a := s.openAccount(t, token, `{"owner_name":"Alice","email":"alice@example.com","initial_balance":"100.00"}`)
+	b := s.openAccount(t, token, `{"owner_name":"Alice","email":"alice+b@example.com","initial_balance":"10.00"}`)
+	c := s.openAccount(t, token, `{"owner_name":"Alice","email":"alice+c@example.com"}`)
+
+	// The second item needs more than b holds even after the first.
+	body := `{"transfers":[
+		{"from_id":1,"to_id":2,"amount":"30.00"},
+		{"from_id":2,"to_id":3,"amount":"50.00"},
+		{"from_id":1,"to_id":3,"amount":"5.00"}
+	]}`
+	rec := s.do(token, http.MethodPost, "/transfers/batch", body)
+	res := expectError(t, rec, http.StatusUnprocessableEntity, types.CodeBatchFailed)
+
+	want := []struct {
+		status types.BatchItemStatus
+		code   string
+	}{
+		{status: types.BatchItemRolledBack},
+		{status: types.BatchItemFailed, code: types.CodeInsufficientFunds},
+		{status: types.BatchItemSkipped},
+	}
+	if len(res.Results) != len(want) {
+		t.Fatalf("results = %+v, want %d items", res.Results, len(want))
+	}
+	for i, w := range want {
+		if got := res.Results[i]; got.Index != i || got.Status != w.status || got.Code != w.code {
+			t.Errorf("item %d = %+v, want status %q code %q", i, got, w.status, w.code)
+		}
+	}
+
+	for _, acc := range []types.Account{a, b, c} {
+		if got := s.balance(t, acc.ID); got != acc.Balance {
+			t.Errorf("account %d balance = %s, want %s", acc.ID, got, acc.Balance)
+		}
+	}
+}
+
+func TestTransferBatchTooLarge(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login(t, "alice@example.com")
+	s.openAccount(t, token, `{"owner_name":"Alice","email":"alice@example.com","initial_balance":"500.00"}`)
+	s.openAccount(t, token, `{"owner_name":"Alice","email":"alice+b@example.com"}`)
+
+	items := make([]string, services.MaxBatchSize+1)
+	for i := range items {
+		items[i] = `{"from_id":1,"to_id":2,"amount":"1.00"}`
+	}
+	rec := s.do(token, http.MethodPost, "/transfers/batch", `{"transfers":[`+strings.Join(items, ",")+`]}`)
+	// The request validator enforces the cap before the batch reaches the
+	// service.
+	res := expectError(t, rec, http.StatusBadRequest, types.CodeValidationFailed)
+	if len(res.Fields) != 1 || res.Fields[0].Field != "transfers" {
+		t.Errorf("fields = %+v, want transfers reported", res.Fields)
+	}
+	if got := s.balance(t, 1); got != 500_00 {
+		t.Errorf("balance = %s, want 500.00", got)
+	}
+}
+
+func TestTransferDryRun(t *testing.T) {
+	s := newTestServer(t)
+	token := s.login(t, "alice@example.com")
+	a := s.openAccount(t, token, `{"owner_name":"Alice","email":"alice@example.com","initial_balance":"40.00"}`)
+	b := s.openAccount(t, token, `{"owner_name":"Alice","email":"alice+b@example.com","initial_balance":"5.00"}`)
+
+	ledgerSize := func(id int64) int {
+		t.Helper()
+		entries, err := s.store.ListTransactions(context.Background(), store.TransactionFilter{AccountID: id, Limit: 100})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return len(entries)
+	}
+	unchanged := func() {
+		t.Helper()
+		for _, acc := range []types.Account{a, b} {
+			if got := s.balance(t, acc.ID); got != acc.Balance {
+				t.Errorf("account %d balance = %s, want %s", acc.ID, got, acc.Balance)
+			}
+			if n := ledgerSize(acc.ID); n != 1 {
+				t.Errorf("account %d has %d ledger entries, want only the opening", acc.ID, n)
+			}
+		}
+	}
+
+	rec := s.do(token, http.MethodPost, "/transfers?dry_run=true", `{"from_id":1,"to_id":2,"amount":"40.01"}`)
+	body := expectError(t, rec, http.StatusUnprocessableEntity, types.CodeInsufficientFunds)
+	if body.Balance == nil || *body.Balance != 40_00 {
+		t.Errorf("balance in 422 body = %v, want 40.00", body.Balance)
+	}
+	unchanged()
+
+	rec = s.do(token, http.MethodPost, "/transfers", `{"from_id":1,"to_id":2,"amount":"15.00"}`, middleware.HeaderDryRun, "true")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("dry run = %d %s", rec.Code, rec.Body)
+	}
+	var res types.TransferResponse
+	decode(t, rec, &res)
+	if res.FromBalance != 25_00 || res.ToBalance == nil || *res.ToBalance != 20_00 {
+		t.Errorf("previewed balances = %s and %v, want 25.00 and 20.00", res.FromBalance, res.ToBalance)
+	}
+	if !res.DryRun {
+		t.Error("dry_run = false, want the response marked as a preview")
+	}
+	unchanged()
+}
+
+func TestTransferHidesOtherUsersBalance(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.login(t, "alice@example.com")
+	bob := s.login(t, "bob@example.com")
+	s.openAccount(t, alice, `{"owner_name":"Alice","email":"alice@example.com","initial_balance":"100.00"}`)
+	s.openAccount(t, bob, `{"owner_name":"Bob","email":"bob@example.com","initial_balance":"500.00"}`)
+
+	for _, dryRun := range []string{"false", "true"} {
+		rec := s.do(alice, http.MethodPost, "/transfers", `{"from_id":1,"to_id":2,"amount":"10.00"}`, middleware.HeaderDryRun, dryRun)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("dry run %s: status = %d %s", dryRun, rec.Code, rec.Body)
+		}
+		if strings.Contains(rec.Body.String(), "to_balance") {
+			t.Errorf("dry run %s: body = %s, want no balance for Bob's account", dryRun, rec.Body)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"BankSystemGoLang/middleware"
+	"BankSystemGoLang/services"
+	"BankSystemGoLang/types"
+)
+
+type WebhookHandler struct {
+	webhooks *services.WebhookService
+}
+
+func NewWebhookHandler(webhooks *services.WebhookService) *WebhookHandler {
+	return &WebhookHandler{webhooks: webhooks}
+}
+
+// Create handles POST /webhooks. The response is the only time the signing
+// secret is shown.
+func (h *WebhookHandler) Create(c echo.Context) error {
+	var req types.WebhookRequest
+	if err := c.Bind(&req); err != nil {
+		return bindError(c, err)
+	}
+
+	webhook, err := h.webhooks.Create(c.Request().Context(), middleware.UserID(c), req)
+	if errors.Is(err, services.ErrInvalidWebhook) || errors.Is(err, services.ErrWebhookAddress) {
+		return respondError(c, http.StatusBadRequest, types.CodeInvalidWebhook, err.Error())
+	}
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, webhook)
+}
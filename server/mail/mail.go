@@ -0,0 +1,61 @@
+// Package mail delivers the email the services send, implementing
+// services.Mailer.
+package mail
+
+import (
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// SMTP sends each message through an SMTP server, authenticating with
+// PLAIN when a username is configured.
+type SMTP struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+// NewSMTP returns a mailer sending from the address from through the
+// server at addr, a host:port.
+func NewSMTP(addr, from, username, password string) *SMTP {
+	m := &SMTP{addr: addr, from: from}
+	if username != "" {
+		host, _, _ := net.SplitHostPort(addr)
+		m.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return m
+}
+
+func (m *SMTP) Send(to, subject, body string) error {
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\nTo: %s\r\nSubject: %s\r\n", m.from, to, subject)
+	msg.WriteString("MIME-Version: 1.0\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n")
+	msg.WriteString(body)
+	return smtp.SendMail(m.addr, m.auth, m.from, []string{to}, []byte(msg.String()))
+}
+
+// Log writes each message to the log instead of sending it, so the server
+// can run without an SMTP server. Bodies carry verification links, so they
+// are only logged when bodies is set, for development; otherwise only the
+// recipient and subject are.
+type Log struct {
+	log    *zap.Logger
+	bodies bool
+}
+
+func NewLog(log *zap.Logger, bodies bool) *Log {
+	return &Log{log: log, bodies: bodies}
+}
+
+func (m *Log) Send(to, subject, body string) error {
+	fields := []zap.Field{zap.String("to", to), zap.String("subject", subject)}
+	if m.bodies {
+		fields = append(fields, zap.String("body", body))
+	}
+	m.log.Info("email not sent, no SMTP server configured", fields...)
+	return nil
+}
@@ -0,0 +1,32 @@
+package mail_test
+
+import (
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"BankSystemGoLang/mail"
+)
+
+func TestLogBodies(t *testing.T) {
+	const body = "Verify your email: http://bank.test/verify?token=secret-token"
+	for _, bodies := range []bool{false, true} {
+		core, logs := observer.New(zapcore.InfoLevel)
+		if err := mail.NewLog(zap.New(core), bodies).Send("a@example.com", "Verify your email", body); err != nil {
+			t.Fatal(err)
+		}
+		entries := logs.All()
+		if len(entries) != 1 {
+			t.Fatalf("bodies %v: %d log entries, want 1", bodies, len(entries))
+		}
+		fields := entries[0].ContextMap()
+		if fields["to"] != "a@example.com" || fields["subject"] != "Verify your email" {
+			t.Errorf("bodies %v: fields = %v, want the recipient and subject", bodies, fields)
+		}
+		if got, _ := fields["body"].(string); (got == body) != bodies {
+			t.Errorf("bodies %v: logged body = %q", bodies, got)
+		}
+	}
+}
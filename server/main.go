@@ -1,18 +1,205 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"BankSystemGoLang/clock"
+	"BankSystemGoLang/config"
+	"BankSystemGoLang/graph"
+	"BankSystemGoLang/handlers"
+	"BankSystemGoLang/logger"
+	"BankSystemGoLang/mail"
+	"BankSystemGoLang/metrics"
+	"BankSystemGoLang/middleware"
+	"BankSystemGoLang/route"
+	"BankSystemGoLang/scheduler"
+	"BankSystemGoLang/seed"
+	"BankSystemGoLang/services"
+	"BankSystemGoLang/store"
+	"BankSystemGoLang/types"
+	"BankSystemGoLang/webhooks"
 )
 
 func main() {
+	memory := flag.Bool("memory", false, "keep all data in process memory instead of the database at db_dsn; it is lost on exit")
+	seedDemo := flag.Bool("seed", false, "create demo users and accounts on startup unless they already exist")
+	logMail := flag.Bool("log-mail", false, "log the full text of email, verification links included, when no SMTP server is configured; for development only")
+	flag.Parse()
+
+	cfg, err := config.Load(os.Getenv("CONFIG_PATH"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid configuration:\n%v\n", err)
+		os.Exit(1)
+	}
+
+	log, err := logger.New(cfg.LogLevel)
+	if err != nil {
+		panic(err)
+	}
+	defer log.Sync()
+
+	rates, err := services.ParseRates(cfg.FXRates)
+	if err != nil {
+		log.Fatal("invalid exchange rates", zap.Error(err))
+	}
+
+	log.Info("starting", zap.Bool("memory", *memory), zap.Bool("seed", *seedDemo))
+
+	var db store.Store
+	if *memory {
+		db = store.NewMemoryStore()
+	} else {
+		sqlite, err := store.OpenSQLite(cfg.DBDSN)
+		if err != nil {
+			log.Fatal("open database", zap.Error(err))
+		}
+		defer sqlite.Close()
+		db = sqlite
+	}
+
+	clk := clock.Real{}
+	accountService := services.NewAccountService(db, cfg.JWTSecret, cfg.InterestRate, rates, clk)
+	authService := services.NewAuthService(db, cfg.JWTSecret, cfg.AdminEmails, clk)
+	scheduleService := services.NewScheduleService(db, accountService, clk)
+	webhookService := services.NewWebhookService(db, accountService, clk)
+	auditService := services.NewAuditService(db, clk)
+
+	if *seedDemo {
+		opened, err := seed.Demo(context.Background(), db, authService, accountService, clk)
+		if err != nil {
+			log.Fatal("seed demo data", zap.Error(err))
+		}
+		log.Info("demo data ready",
+			zap.Int("accounts_opened", opened),
+			zap.Strings("users", seed.DemoEmails()),
+			zap.String("password", seed.DemoPassword))
+	}
+
+	var mailer services.Mailer = mail.NewLog(log, *logMail)
+	if cfg.SMTPAddr != "" {
+		mailer = mail.NewSMTP(cfg.SMTPAddr, cfg.MailFrom, cfg.SMTPUsername, cfg.SMTPPassword)
+	}
+	verificationService := services.NewVerificationService(db, mailer, strings.TrimSuffix(cfg.PublicURL, "/")+"/verify", clk)
+
+	m := metrics.New(accountService.Count)
+	accountService.Subscribe(m.Observe)
+	dispatcher := webhooks.NewDispatcher(webhookService, log)
+	accountService.Subscribe(dispatcher.Publish)
+
+	schema, err := graph.NewSchema(accountService, log)
+	if err != nil {
+		log.Fatal("parse GraphQL schema", zap.Error(err))
+	}
+	spec, err := route.OpenAPI()
+	if err != nil {
+		log.Fatal("build OpenAPI document", zap.Error(err))
+	}
+
+	var (
+		limiter     middleware.Limiter          = middleware.NewRateLimiter(cfg.RateLimitPerMinute, clk)
+		idempotency middleware.IdempotencyStore = middleware.NewIdempotencyCache(middleware.IdempotencyTTL, clk)
+	)
+	if cfg.RedisURL != "" {
+		// Without Redis each instance limits and deduplicates on its own,
+		// which still protects a single instance, so it is not fatal.
+		client, err := middleware.ConnectRedis(context.Background(), cfg.RedisURL, 2*time.Second)
+		if err != nil {
+			log.Warn("redis unavailable; rate limits and idempotency keys are kept in process", zap.Error(err))
+		} else {
+			defer client.Close()
+			limiter = middleware.NewRedisRateLimiter(client, cfg.RateLimitPerMinute, clk, log)
+			idempotency = middleware.NewRedisIdempotencyStore(client, middleware.IdempotencyTTL)
+		}
+	}
+
 	e := echo.New()
+	e.HideBanner = true
+	e.HidePort = true
+	e.HTTPErrorHandler = handlers.NewHTTPErrorHandler(log)
+	e.Binder = handlers.NewBinder()
+	e.Use(middleware.RequestID())
+	e.Use(middleware.RequestLogger(log))
+	e.Use(m.Middleware())
+	e.Use(middleware.Recover(log))
+	e.Use(middleware.CORS(cfg.AllowedOrigins))
+	e.Use(middleware.Timeout(cfg.RequestTimeout))
 
-	e.GET("/health", func(c echo.Context) error {
-		return c.String(200, "BankSystem is running 🚀")
+	route.Register(e, route.Handlers{
+		Health:    handlers.NewHealthHandler(db, log),
+		Metrics:   m.Handler(),
+		Auth:      handlers.NewAuthHandler(authService, verificationService, log),
+		Accounts:  handlers.NewAccountHandler(accountService, clk),
+		Holds:     handlers.NewHoldHandler(accountService),
+		Transfers: handlers.NewTransferHandler(accountService),
+		Schedules: handlers.NewScheduleHandler(accountService, scheduleService),
+		Webhooks:  handlers.NewWebhookHandler(webhookService),
+		Audit:     handlers.NewAuditHandler(auditService, clk),
+		GraphQL:   handlers.NewGraphQLHandler(schema),
+		Docs:      handlers.NewDocsHandler(spec, route.DocsPage()),
+	}, route.Middleware{
+		RequireAuth:  middleware.RequireAuth(authService),
+		RequireAdmin: middleware.RequireRole(types.RoleAdmin),
+		Idempotent:   middleware.Idempotent(idempotency),
+		RateLimit:    middleware.RateLimit(limiter),
+		Audit:        middleware.Audit(auditService, log),
 	})
 
-	fmt.Println("Server running on :1323")
-	e.Logger.Fatal(e.Start(":1323"))
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var background sync.WaitGroup
+	background.Go(func() { scheduler.New(scheduleService, cfg.SchedulerInterval, log).Run(ctx) })
+	background.Go(func() { dispatcher.Run(ctx) })
+
+	if err := serve(ctx, log, e, cfg.Address(), cfg.ShutdownTimeout); err != nil {
+		log.Error("server stopped", zap.Error(err))
+	}
+	// Stop the background jobs too when serve failed on its own, and let
+	// work in progress finish before the database is closed.
+	stop()
+	background.Wait()
+}
+
+// serve runs the server until ctx is cancelled, then gives in-flight
+// requests up to timeout to finish before returning.
+func serve(ctx context.Context, log *zap.Logger, e *echo.Echo, addr string, timeout time.Duration) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	e.Listener = ln
+	log.Info("server running", zap.String("addr", ln.Addr().String()))
+
+	errCh := make(chan error, 1)
+	go func() {
+		if err := e.Start(addr); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- err
+		}
+		close(errCh)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	log.Info("shutting down", zap.Duration("timeout", timeout))
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return e.Shutdown(shutdownCtx)
 }
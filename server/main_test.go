@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+func TestServeFinishesInFlightRequests(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	e := echo.New()
+	e.HideBanner = true
+	e.GET("/slow", func(c echo.Context) error {
+		close(started)
+		<-release
+		return c.String(http.StatusOK, "done")
+	})
+
+	addr := freeAddr(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	served := make(chan error, 1)
+	go func() { served <- serve(ctx, zap.NewNop(), e, addr, 5*time.Second) }()
+
+	type result struct {
+		body string
+		err  error
+	}
+	got := make(chan result, 1)
+	go func() {
+		res, err := getWhenUp(ctx, "http://"+addr+"/slow")
+		if err != nil {
+			got <- result{err: err}
+			return
+		}
+		defer res.Body.Close()
+		body, err := io.ReadAll(res.Body)
+		got <- result{string(body), err}
+	}()
+
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("request never reached the handler")
+	}
+	cancel()
+	// Shutdown has begun; the request must still be allowed to finish.
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+
+	if r := <-got; r.err != nil || r.body != "done" {
+		t.Errorf("in-flight request got %q, %v; want done", r.body, r.err)
+	}
+	if err := <-served; err != nil {
+		t.Errorf("serve returned %v", err)
+	}
+}
+
+// freeAddr returns a loopback address with a port nothing listens on.
+func freeAddr(t *testing.T) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	return ln.Addr().String()
+}
+
+// getWhenUp retries the GET until the server accepts connections.
+func getWhenUp(ctx context.Context, url string) (*http.Response, error) {
+	for {
+		res, err := http.Get(url)
+		if err == nil {
+			return res, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
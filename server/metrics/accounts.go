@@ -0,0 +1,45 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// accountCollector reports the total number of accounts, read from the
+// store at scrape time so it stays correct across restarts.
+type accountCollector struct {
+	count func(ctx context.Context) (int, error)
+	desc  *prometheus.Desc
+}
+
+// countTimeout bounds the store read of a scrape; it matches Prometheus's
+// default scrape timeout.
+const countTimeout = 10 * time.Second
+
+func newAccountCollector(count func(ctx context.Context) (int, error)) *accountCollector {
+	return &accountCollector{
+		count: count,
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "accounts"),
+			"Number of accounts, open and closed.",
+			nil, nil,
+		),
+	}
+}
+
+func (c *accountCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *accountCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), countTimeout)
+	defer cancel()
+	n, err := c.count(ctx)
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(c.desc, err)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(n))
+}
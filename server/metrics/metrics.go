@@ -0,0 +1,107 @@
+// Package metrics exposes Prometheus metrics for HTTP traffic and for the
+// money movements committed by the account service.
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"BankSystemGoLang/types"
+)
+
+const namespace = "banksystem"
+
+// Metrics owns the registry and every collector served on /metrics.
+type Metrics struct {
+	registry *prometheus.Registry
+	requests *prometheus.CounterVec
+	latency  *prometheus.HistogramVec
+	events   *prometheus.CounterVec
+}
+
+// New registers the HTTP, money movement and account collectors, plus the
+// standard Go runtime and process collectors, on a fresh registry.
+// countAccounts is called on every scrape to report the number of accounts.
+func New(countAccounts func(ctx context.Context) (int, error)) *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests handled, by method, route and status.",
+		}, []string{"method", "route", "status"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency, by method, route and status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		events: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "money_movements_total",
+			Help:      "Committed deposits, withdrawals and transfers, by type.",
+		}, []string{"type"}),
+	}
+
+	// Start every movement type at zero so rate() works before the first one.
+	for _, t := range []types.EventType{types.EventDeposit, types.EventWithdrawal, types.EventTransfer} {
+		m.events.WithLabelValues(string(t))
+	}
+
+	m.registry.MustRegister(
+		m.requests,
+		m.latency,
+		m.events,
+		newAccountCollector(countAccounts),
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return m
+}
+
+// Handler serves the registry in the Prometheus exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// Observe counts a committed money movement. It is meant to be passed to
+// AccountService.Subscribe.
+func (m *Metrics) Observe(event types.Event) {
+	m.events.WithLabelValues(string(event.Type)).Inc()
+}
+
+// Middleware records the count and latency of every request. The route
+// label is the matched route template, so /accounts/1 and /accounts/2 share
+// a series, and unmatched paths are grouped under "unmatched".
+func (m *Metrics) Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			if err := next(c); err != nil {
+				// Let the error handler write the response so the final
+				// status is known before recording it.
+				c.Error(err)
+			}
+
+			route := c.Path()
+			if route == "" || route == "/*" {
+				route = "unmatched"
+			}
+			labels := prometheus.Labels{
+				"method": c.Request().Method,
+				"route":  route,
+				"status": strconv.Itoa(c.Response().Status),
+			}
+			m.requests.With(labels).Inc()
+			m.latency.With(labels).Observe(time.Since(start).Seconds())
+			return nil
+		}
+	}
+}
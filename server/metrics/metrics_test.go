@@ -0,0 +1,78 @@
+package metrics_test
+
+import (
+	"bufio"
+	"context"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"BankSystemGoLang/clock"
+	"BankSystemGoLang/metrics"
+	"BankSystemGoLang/services"
+	"BankSystemGoLang/store"
+	"BankSystemGoLang/types"
+)
+
+// scrape fetches /metrics and returns the value of every sample by its
+// name and labels as written, such as `banksystem_accounts`.
+func scrape(t *testing.T, m *metrics.Metrics) map[string]string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /metrics = %d", rec.Code)
+	}
+	samples := make(map[string]string)
+	sc := bufio.NewScanner(rec.Body)
+	for sc.Scan() {
+		line := sc.Text()
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		if i := strings.LastIndexByte(line, ' '); i > 0 {
+			samples[line[:i]] = line[i+1:]
+		}
+	}
+	return samples
+}
+
+func TestTransferCounter(t *testing.T) {
+	ctx := context.Background()
+	accounts := services.NewAccountService(store.NewMemoryStore(), "test-secret", big.NewRat(0, 1), services.StaticRates{}, clock.Real{})
+	m := metrics.New(accounts.Count)
+	accounts.Subscribe(m.Observe)
+
+	transfers := `banksystem_money_movements_total{type="` + string(types.EventTransfer) + `"}`
+	if got := scrape(t, m)[transfers]; got != "0" {
+		t.Fatalf("%s before any transfer = %q, want 0", transfers, got)
+	}
+
+	a, err := accounts.Create(ctx, 1, types.CreateAccountRequest{OwnerName: "A", Email: "a@example.com", InitialBalance: 10_00})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := accounts.Create(ctx, 1, types.CreateAccountRequest{OwnerName: "B", Email: "b@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range 2 {
+		if _, _, _, err := accounts.Transfer(ctx, a.ID, b.ID, 1_00); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A failed transfer commits nothing and is not counted.
+	if _, _, _, err := accounts.Transfer(ctx, a.ID, b.ID, 100_00); err == nil {
+		t.Fatal("overdrawing transfer succeeded")
+	}
+
+	samples := scrape(t, m)
+	if got := samples[transfers]; got != "2" {
+		t.Errorf("%s = %q, want 2", transfers, got)
+	}
+	if got := samples["banksystem_accounts"]; got != "2" {
+		t.Errorf("banksystem_accounts = %q, want 2", got)
+	}
+}
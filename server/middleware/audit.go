@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"BankSystemGoLang/services"
+	"BankSystemGoLang/types"
+)
+
+// skipAuditKey marks a request whose handler changed nothing.
+const skipAuditKey = "skip_audit"
+
+// Audit returns a factory for middleware recording an admin's request to
+// the route in the audit log as action on the account in its :id
+// parameter. An entry is written whenever the action applied, told by a
+// 2xx response having been sent, even if the handler then returned an
+// error, unless the handler called SkipAudit; the request body is kept
+// alongside it. Requests by non-admins pass through unrecorded, so it must
+// run after RequireAuth. A failed audit write cannot undo the action, so it
+// is logged instead.
+func Audit(audit *services.AuditService, log *zap.Logger) func(action types.AuditAction) echo.MiddlewareFunc {
+	return func(action types.AuditAction) echo.MiddlewareFunc {
+		return func(next echo.HandlerFunc) echo.HandlerFunc {
+			return func(c echo.Context) error {
+				if Role(c) != types.RoleAdmin {
+					return next(c)
+				}
+
+				body, err := io.ReadAll(c.Request().Body)
+				if err != nil {
+					return err
+				}
+				c.Request().Body = io.NopCloser(bytes.NewReader(body))
+
+				err = next(c)
+
+				res := c.Response()
+				skip, _ := c.Get(skipAuditKey).(bool)
+				if skip || !res.Committed || res.Status < http.StatusOK || res.Status >= http.StatusMultipleChoices {
+					return err
+				}
+				// The action has been applied, so it is recorded even when the
+				// request's context has ended since.
+				ctx := context.WithoutCancel(c.Request().Context())
+				accountID, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+				if _, auditErr := audit.Record(ctx, UserID(c), action, accountID, snapshot(body)); auditErr != nil {
+					log.Error("write audit entry",
+						zap.String("request_id", RequestIDFrom(c)),
+						zap.String("action", string(action)),
+						zap.Int64("account_id", accountID),
+						zap.Error(auditErr),
+					)
+				}
+				return err
+			}
+		}
+	}
+}
+
+// SkipAudit tells Audit not to record a successful request that turned out
+// to change nothing.
+func SkipAudit(c echo.Context) {
+	c.Set(skipAuditKey, true)
+}
+
+// snapshot returns body compacted as the JSON kept with an audit entry, or
+// nil when there is none. A body that is not JSON is kept as a JSON string.
+func snapshot(body []byte) json.RawMessage {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+	var compact bytes.Buffer
+	if json.Compact(&compact, body) == nil {
+		return compact.Bytes()
+	}
+	quoted, _ := json.Marshal(string(body))
+	return quoted
+}
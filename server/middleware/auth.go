@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"BankSystemGoLang/services"
+	"BankSystemGoLang/types"
+)
+
+const (
+	userIDKey = "user_id"
+	roleKey   = "role"
+)
+
+// RequireAuth rejects requests without a valid "Authorization: Bearer"
+// token and stores the authenticated user ID and role in the context.
+func RequireAuth(auth *services.AuthService) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			header := c.Request().Header.Get(echo.HeaderAuthorization)
+			raw, ok := strings.CutPrefix(header, "Bearer ")
+			if !ok || raw == "" {
+				return unauthorized(c, types.CodeUnauthorized, "missing bearer token")
+			}
+
+			userID, role, err := auth.ParseToken(raw)
+			switch {
+			case errors.Is(err, services.ErrTokenExpired):
+				return unauthorized(c, types.CodeTokenExpired, err.Error())
+			case errors.Is(err, services.ErrInvalidToken):
+				return unauthorized(c, types.CodeInvalidToken, err.Error())
+			case err != nil:
+				return err
+			}
+
+			c.Set(userIDKey, userID)
+			c.Set(roleKey, role)
+			return next(c)
+		}
+	}
+}
+
+// RequireRole rejects authenticated users whose token does not grant role
+// with a 403. It must run after RequireAuth.
+func RequireRole(role types.Role) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if Role(c) != role {
+				return c.JSON(http.StatusForbidden, types.ErrorResponse{
+					Code:      types.CodeForbidden,
+					Error:     string(role) + " role required",
+					RequestID: RequestIDFrom(c),
+				})
+			}
+			return next(c)
+		}
+	}
+}
+
+// UserID returns the authenticated user ID set by RequireAuth.
+func UserID(c echo.Context) int64 {
+	id, _ := c.Get(userIDKey).(int64)
+	return id
+}
+
+// Role returns the authenticated user's role set by RequireAuth.
+func Role(c echo.Context) types.Role {
+	role, _ := c.Get(roleKey).(types.Role)
+	return role
+}
+
+func unauthorized(c echo.Context, code, message string) error {
+	return c.JSON(http.StatusUnauthorized, types.ErrorResponse{
+		Code:      code,
+		Error:     message,
+		RequestID: RequestIDFrom(c),
+	})
+}
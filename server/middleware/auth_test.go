@@ -0,0 +1,128 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+
+	"BankSystemGoLang/clock"
+	"BankSystemGoLang/middleware"
+	"BankSystemGoLang/services"
+	"BankSystemGoLang/store"
+	"BankSystemGoLang/types"
+)
+
+const testSecret = "middleware-test-secret-32-bytes!"
+
+// signToken returns a token for userID with role that expires at exp,
+// signed as AuthService signs them.
+func signToken(t *testing.T, userID int64, role types.Role, exp time.Time) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":  strconv.FormatInt(userID, 10),
+		"role": string(role),
+		"exp":  exp.Unix(),
+	})
+	signed, err := token.SignedString([]byte(testSecret))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+// serveAuthed sends a GET through RequireAuth and then extra to a handler
+// answering with the authenticated user ID and role.
+func serveAuthed(authorization string, extra ...echo.MiddlewareFunc) *httptest.ResponseRecorder {
+	auth := services.NewAuthService(store.NewMemoryStore(), testSecret, nil, clock.Real{})
+	e := echo.New()
+	e.GET("/", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, map[string]any{"user_id": middleware.UserID(c), "role": middleware.Role(c)})
+	}, append([]echo.MiddlewareFunc{middleware.RequireAuth(auth)}, extra...)...)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if authorization != "" {
+		req.Header.Set(echo.HeaderAuthorization, authorization)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRequireAuth(t *testing.T) {
+	tests := []struct {
+		name          string
+		authorization string
+		status        int
+		code          string
+	}{
+		{name: "valid token", authorization: "Bearer " + signToken(t, 7, types.RoleUser, time.Now().Add(time.Hour)), status: http.StatusOK},
+		{name: "expired token", authorization: "Bearer " + signToken(t, 7, types.RoleUser, time.Now().Add(-time.Minute)), status: http.StatusUnauthorized, code: types.CodeTokenExpired},
+		{name: "malformed token", authorization: "Bearer not-a-jwt", status: http.StatusUnauthorized, code: types.CodeInvalidToken},
+		{name: "missing header", status: http.StatusUnauthorized, code: types.CodeUnauthorized},
+		{name: "not a bearer token", authorization: "Basic dXNlcjpwYXNz", status: http.StatusUnauthorized, code: types.CodeUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serveAuthed(tt.authorization)
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.status, rec.Body)
+			}
+			if tt.code == "" {
+				var body struct {
+					UserID int64      `json:"user_id"`
+					Role   types.Role `json:"role"`
+				}
+				if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+					t.Fatal(err)
+				}
+				if body.UserID != 7 || body.Role != types.RoleUser {
+					t.Errorf("authenticated as %d (%s), want 7 (user)", body.UserID, body.Role)
+				}
+				return
+			}
+			var body types.ErrorResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if body.Code != tt.code {
+				t.Errorf("code = %q, want %q", body.Code, tt.code)
+			}
+		})
+	}
+}
+
+func TestRequireRole(t *testing.T) {
+	tests := []struct {
+		name   string
+		role   types.Role
+		status int
+	}{
+		{name: "admin", role: types.RoleAdmin, status: http.StatusOK},
+		{name: "user", role: types.RoleUser, status: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := signToken(t, 7, tt.role, time.Now().Add(time.Hour))
+			rec := serveAuthed("Bearer "+token, middleware.RequireRole(types.RoleAdmin))
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.status, rec.Body)
+			}
+			if tt.status == http.StatusOK {
+				return
+			}
+			var body types.ErrorResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if body.Code != types.CodeForbidden {
+				t.Errorf("code = %q, want %q", body.Code, types.CodeForbidden)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	echomiddleware "github.com/labstack/echo/v4/middleware"
+)
+
+// Conditional request headers, which Echo has no constants for. Accounts
+// are sent with their version as ETag and writes honour If-Match.
+const (
+	HeaderETag    = "ETag"
+	HeaderIfMatch = "If-Match"
+)
+
+// CORS lets browser clients served from allowedOrigins call the API,
+// answering preflight OPTIONS requests itself. With no origins configured
+// no CORS headers are sent and browsers only allow same-origin calls.
+func CORS(allowedOrigins []string) echo.MiddlewareFunc {
+	if len(allowedOrigins) == 0 {
+		return func(next echo.HandlerFunc) echo.HandlerFunc { return next }
+	}
+	return echomiddleware.CORSWithConfig(echomiddleware.CORSConfig{
+		AllowOrigins: allowedOrigins,
+		AllowMethods: []string{
+			http.MethodGet, http.MethodHead, http.MethodPost,
+			http.MethodPut, http.MethodPatch, http.MethodDelete,
+		},
+		AllowHeaders: []string{
+			echo.HeaderAuthorization, echo.HeaderContentType,
+			HeaderIdempotencyKey, HeaderIfMatch, HeaderDryRun, echo.HeaderXRequestID,
+		},
+		ExposeHeaders: []string{
+			echo.HeaderXRequestID, HeaderIdempotentReplayed, echo.HeaderRetryAfter, HeaderETag,
+		},
+		MaxAge: 600,
+	})
+}
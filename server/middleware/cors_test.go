@@ -0,0 +1,72 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"BankSystemGoLang/middleware"
+)
+
+func TestCORS(t *testing.T) {
+	e := echo.New()
+	e.Use(middleware.CORS([]string{"https://app.example.com"}))
+	e.GET("/accounts", func(c echo.Context) error { return c.NoContent(http.StatusNoContent) })
+
+	tests := []struct {
+		name          string
+		method        string
+		origin        string
+		wantOrigin    string
+		wantPreflight bool
+	}{
+		{name: "preflight from an allowed origin", method: http.MethodOptions, origin: "https://app.example.com", wantOrigin: "https://app.example.com", wantPreflight: true},
+		{name: "simple request from an allowed origin", method: http.MethodGet, origin: "https://app.example.com", wantOrigin: "https://app.example.com"},
+		{name: "simple request from another origin", method: http.MethodGet, origin: "https://evil.example.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/accounts", nil)
+			req.Header.Set(echo.HeaderOrigin, tt.origin)
+			if tt.method == http.MethodOptions {
+				req.Header.Set(echo.HeaderAccessControlRequestMethod, http.MethodPost)
+				req.Header.Set(echo.HeaderAccessControlRequestHeaders, "Authorization, Idempotency-Key")
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			if got := rec.Header().Get(echo.HeaderAccessControlAllowOrigin); got != tt.wantOrigin {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tt.wantOrigin)
+			}
+			if !tt.wantPreflight {
+				return
+			}
+			if rec.Code != http.StatusNoContent {
+				t.Errorf("preflight status = %d, want 204", rec.Code)
+			}
+			allowed := rec.Header().Get(echo.HeaderAccessControlAllowHeaders)
+			for _, h := range []string{echo.HeaderAuthorization, middleware.HeaderIdempotencyKey} {
+				if !strings.Contains(allowed, h) {
+					t.Errorf("Access-Control-Allow-Headers = %q, missing %s", allowed, h)
+				}
+			}
+		})
+	}
+}
+
+func TestCORSWithoutOrigins(t *testing.T) {
+	e := echo.New()
+	e.Use(middleware.CORS(nil))
+	e.GET("/accounts", func(c echo.Context) error { return c.NoContent(http.StatusNoContent) })
+
+	req := httptest.NewRequest(http.MethodGet, "/accounts", nil)
+	req.Header.Set(echo.HeaderOrigin, "https://app.example.com")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	if got := rec.Header().Get(echo.HeaderAccessControlAllowOrigin); got != "" {
+		t.Errorf("Access-Control-Allow-Origin = %q, want none", got)
+	}
+}
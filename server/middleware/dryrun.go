@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+// HeaderDryRun asks a route that supports previews to validate and report
+// the outcome of the request without applying it, as the dry_run query
+// parameter does.
+const HeaderDryRun = "X-Dry-Run"
+
+// DryRun reports whether the request asks for a preview through the
+// dry_run query parameter or the X-Dry-Run header. A value that is not a
+// boolean is an error rather than false, so a mistyped preview is never
+// applied.
+func DryRun(c echo.Context) (bool, error) {
+	for _, v := range []struct{ name, value string }{
+		{"dry_run", c.QueryParam("dry_run")},
+		{HeaderDryRun, c.Request().Header.Get(HeaderDryRun)},
+	} {
+		if v.value == "" {
+			continue
+		}
+		dry, err := strconv.ParseBool(v.value)
+		if err != nil {
+			return false, fmt.Errorf("%s must be true or false, got %q", v.name, v.value)
+		}
+		if dry {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,223 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"BankSystemGoLang/clock"
+	"BankSystemGoLang/services"
+	"BankSystemGoLang/types"
+)
+
+const (
+	// HeaderIdempotencyKey names the request header carrying the client key.
+	HeaderIdempotencyKey = "Idempotency-Key"
+	// HeaderIdempotentReplayed is set on responses served from the cache.
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+
+	// IdempotencyTTL is how long a key's stored result is kept.
+	IdempotencyTTL = 24 * time.Hour
+
+	maxIdempotencyKeyLength = 255
+)
+
+// StoredResponse is the response kept for an Idempotency-Key.
+type StoredResponse struct {
+	Status      int    `json:"status"`
+	ContentType string `json:"content_type"`
+	Body        []byte `json:"body"`
+}
+
+// IdempotencyStore remembers the outcome of requests sent with an
+// Idempotency-Key so that retries can be answered without re-running them.
+// IdempotencyCache keeps them in process memory; RedisIdempotencyStore
+// shares them across instances.
+type IdempotencyStore interface {
+	// Begin reserves key for a new request whose body hashes to
+	// fingerprint, or returns the response stored when key was already
+	// used. A nil response with a nil error means the caller should run
+	// the request.
+	Begin(ctx context.Context, key, fingerprint string) (*StoredResponse, error)
+	// Complete stores the response of the request that reserved key.
+	Complete(ctx context.Context, key, fingerprint string, res StoredResponse) error
+	// Release forgets key so the request can be retried with it.
+	Release(ctx context.Context, key string) error
+}
+
+type idempotentResult struct {
+	fingerprint string
+	done        bool
+	response    StoredResponse
+	expiresAt   time.Time
+}
+
+// IdempotencyCache is the in-process IdempotencyStore.
+type IdempotencyCache struct {
+	mu        sync.Mutex
+	results   map[string]*idempotentResult
+	ttl       time.Duration
+	clock     clock.Clock
+	lastSweep time.Time
+}
+
+func NewIdempotencyCache(ttl time.Duration, clk clock.Clock) *IdempotencyCache {
+	return &IdempotencyCache{
+		results: make(map[string]*idempotentResult),
+		ttl:     ttl,
+		clock:   clk,
+	}
+}
+
+func (c *IdempotencyCache) Begin(_ context.Context, key, fingerprint string) (*StoredResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.clock.Now()
+	c.sweep(now)
+
+	if r, ok := c.results[key]; ok && now.Before(r.expiresAt) {
+		switch {
+		case r.fingerprint != fingerprint:
+			return nil, errKeyReused
+		case !r.done:
+			return nil, errKeyInFlight
+		}
+		return &r.response, nil
+	}
+
+	c.results[key] = &idempotentResult{fingerprint: fingerprint, expiresAt: now.Add(c.ttl)}
+	return nil, nil
+}
+
+func (c *IdempotencyCache) Complete(_ context.Context, key, fingerprint string, res StoredResponse) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if r, ok := c.results[key]; ok && r.fingerprint == fingerprint {
+		r.done, r.response = true, res
+	}
+	return nil
+}
+
+func (c *IdempotencyCache) Release(_ context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.results, key)
+	return nil
+}
+
+// sweep drops expired results at most once a minute; c.mu must be held.
+func (c *IdempotencyCache) sweep(now time.Time) {
+	if now.Sub(c.lastSweep) < time.Minute {
+		return
+	}
+	c.lastSweep = now
+	for key, r := range c.results {
+		if !now.Before(r.expiresAt) {
+			delete(c.results, key)
+		}
+	}
+}
+
+var (
+	errKeyReused   = fmt.Errorf("%s was already used with a different request body", HeaderIdempotencyKey)
+	errKeyInFlight = fmt.Errorf("a request with this %s is still being processed", HeaderIdempotencyKey)
+)
+
+// Idempotent makes a route honour the Idempotency-Key header. The first
+// request with a key runs normally and its response is stored; repeats with
+// the same key and body get the stored response back. Server errors and
+// conflicts with a concurrent writer are not stored, since a retry of
+// either can succeed. Keys are scoped to the authenticated user and request
+// path, so it must run after RequireAuth. Dry runs change nothing and are
+// never stored, so a preview cannot be replayed in place of the request it
+// previewed.
+func Idempotent(store IdempotencyStore) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			clientKey := c.Request().Header.Get(HeaderIdempotencyKey)
+			if dry, err := DryRun(c); clientKey == "" || dry || err != nil {
+				return next(c)
+			}
+			if len(clientKey) > maxIdempotencyKeyLength {
+				return idempotencyError(c, http.StatusBadRequest, types.CodeBadRequest,
+					fmt.Sprintf("%s must be at most %d characters", HeaderIdempotencyKey, maxIdempotencyKeyLength))
+			}
+
+			body, err := io.ReadAll(c.Request().Body)
+			if err != nil {
+				return err
+			}
+			c.Request().Body = io.NopCloser(bytes.NewReader(body))
+			sum := sha256.Sum256(body)
+			fingerprint := hex.EncodeToString(sum[:])
+
+			req := c.Request()
+			ctx := req.Context()
+			key := fmt.Sprintf("%d %s %s %s", UserID(c), req.Method, req.URL.Path, clientKey)
+			stored, err := store.Begin(ctx, key, fingerprint)
+			switch {
+			case err == errKeyReused:
+				return idempotencyError(c, http.StatusUnprocessableEntity, types.CodeIdempotencyKeyReused, err.Error())
+			case err == errKeyInFlight:
+				return idempotencyError(c, http.StatusConflict, types.CodeIdempotencyKeyInFlight, err.Error())
+			case err != nil:
+				return err
+			case stored != nil:
+				c.Response().Header().Set(HeaderIdempotentReplayed, "true")
+				return c.Blob(stored.Status, stored.ContentType, stored.Body)
+			}
+
+			rec := &recordingWriter{ResponseWriter: c.Response().Writer}
+			c.Response().Writer = rec
+			err = next(c)
+			if err != nil {
+				c.Error(err)
+			}
+
+			// The response has been sent, so a failure to store it can only
+			// be reported to the error handler, which logs it. It is stored
+			// even when the request's context has ended since, or the key
+			// would stay in flight until it expires. A request that was
+			// cancelled or timed out, or got no response at all, has no
+			// outcome worth replaying, so its key is released instead.
+			ctx = context.WithoutCancel(ctx)
+			status := c.Response().Status
+			if status >= http.StatusInternalServerError || !c.Response().Committed ||
+				errors.Is(err, services.ErrConcurrentUpdate) ||
+				errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+				return store.Release(ctx, key)
+			}
+			return store.Complete(ctx, key, fingerprint, StoredResponse{
+				Status:      status,
+				ContentType: c.Response().Header().Get(echo.HeaderContentType),
+				Body:        rec.body.Bytes(),
+			})
+		}
+	}
+}
+
+func idempotencyError(c echo.Context, status int, code, message string) error {
+	return c.JSON(status, types.ErrorResponse{Code: code, Error: message, RequestID: RequestIDFrom(c)})
+}
+
+// recordingWriter copies everything written to the client.
+type recordingWriter struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recordingWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
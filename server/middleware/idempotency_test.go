@@ -0,0 +1,118 @@
+package middleware_test
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"BankSystemGoLang/clock"
+	"BankSystemGoLang/handlers"
+	"BankSystemGoLang/middleware"
+	"BankSystemGoLang/services"
+	"BankSystemGoLang/store"
+	"BankSystemGoLang/types"
+)
+
+// idempotentServer routes POST /deposit through Idempotent to handler.
+func idempotentServer(handler echo.HandlerFunc) *echo.Echo {
+	e := echo.New()
+	e.HTTPErrorHandler = handlers.NewHTTPErrorHandler(zap.NewNop())
+	cache := middleware.NewIdempotencyCache(middleware.IdempotencyTTL, clock.NewMock(time.Now()))
+	e.POST("/deposit", handler, middleware.Idempotent(cache))
+	return e
+}
+
+func postWithKey(e *echo.Echo, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/deposit", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(middleware.HeaderIdempotencyKey, key)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestIdempotentReplay(t *testing.T) {
+	st := store.NewMemoryStore()
+	accounts := services.NewAccountService(st, "test-secret", big.NewRat(0, 1), services.StaticRates{}, clock.Real{})
+	account, err := accounts.Create(context.Background(), 1, types.CreateAccountRequest{OwnerName: "A", Email: "a@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := idempotentServer(func(c echo.Context) error {
+		var req types.AmountRequest
+		if err := c.Bind(&req); err != nil {
+			return err
+		}
+		updated, err := accounts.Deposit(c.Request().Context(), account.ID, req.Amount, 0)
+		if err != nil {
+			return err
+		}
+		return c.JSON(http.StatusOK, types.BalanceResponse{AccountID: updated.ID, Balance: updated.Balance, Version: updated.Version})
+	})
+
+	first := postWithKey(e, "deposit-1", `{"amount":"10.00"}`)
+	if first.Code != http.StatusOK {
+		t.Fatalf("first = %d %s", first.Code, first.Body)
+	}
+	for range 2 {
+		replay := postWithKey(e, "deposit-1", `{"amount":"10.00"}`)
+		if replay.Code != http.StatusOK || replay.Body.String() != first.Body.String() {
+			t.Errorf("replay = %d %s, want the first response %s", replay.Code, replay.Body, first.Body)
+		}
+		if replay.Header().Get(middleware.HeaderIdempotentReplayed) != "true" {
+			t.Errorf("replay lacks %s", middleware.HeaderIdempotentReplayed)
+		}
+	}
+
+	got, err := accounts.Get(context.Background(), account.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Balance != 10_00 {
+		t.Errorf("balance = %s, want 10.00 from a single deposit", got.Balance)
+	}
+
+	if rec := postWithKey(e, "deposit-1", `{"amount":"20.00"}`); rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("reused key with another body = %d, want 422", rec.Code)
+	}
+}
+
+func TestIdempotentRetriesTransientFailures(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{name: "server error", err: echo.NewHTTPError(http.StatusServiceUnavailable)},
+		{name: "concurrent update", err: services.ErrConcurrentUpdate},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			e := idempotentServer(func(c echo.Context) error {
+				calls++
+				if calls == 1 {
+					return tt.err
+				}
+				return c.String(http.StatusOK, "applied")
+			})
+
+			if rec := postWithKey(e, "k", `{}`); rec.Code < http.StatusBadRequest {
+				t.Fatalf("first attempt = %d, want a failure", rec.Code)
+			}
+			rec := postWithKey(e, "k", `{}`)
+			if rec.Code != http.StatusOK || rec.Body.String() != "applied" {
+				t.Errorf("retry = %d %s, want it to run again", rec.Code, rec.Body)
+			}
+			if calls != 2 {
+				t.Errorf("handler ran %d times, want 2", calls)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// RequestLogger writes one structured log line per request with its method,
+// path, status, latency and request ID. Server errors are logged at error
+// level and client errors at warn.
+func RequestLogger(log *zap.Logger) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			if err := next(c); err != nil {
+				// Let the error handler write the response so the final
+				// status is known before logging.
+				c.Error(err)
+			}
+
+			status := c.Response().Status
+			level := zapcore.InfoLevel
+			switch {
+			case status >= http.StatusInternalServerError:
+				level = zapcore.ErrorLevel
+			case status >= http.StatusBadRequest:
+				level = zapcore.WarnLevel
+			}
+
+			req := c.Request()
+			log.Log(level, "request",
+				zap.String("request_id", RequestIDFrom(c)),
+				zap.String("method", req.Method),
+				zap.String("path", req.URL.Path),
+				zap.String("route", c.Path()),
+				zap.Int("status", status),
+				zap.Duration("latency", time.Since(start)),
+				zap.String("remote_ip", c.RealIP()),
+			)
+			return nil
+		}
+	}
+}
+
+// Recover turns a panicking handler into a 500 and logs the panic value
+// with its stack trace.
+func Recover(log *zap.Logger) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) (err error) {
+			defer func() {
+				r := recover()
+				if r == nil {
+					return
+				}
+				if r == http.ErrAbortHandler {
+					panic(r)
+				}
+
+				perr, ok := r.(error)
+				if !ok {
+					perr = fmt.Errorf("%v", r)
+				}
+				log.Error("panic recovered",
+					zap.String("request_id", RequestIDFrom(c)),
+					zap.Error(perr),
+					zap.ByteString("stack", debug.Stack()),
+				)
+				err = fmt.Errorf("panic: %w", perr)
+			}()
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package middleware_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"BankSystemGoLang/handlers"
+	"BankSystemGoLang/middleware"
+)
+
+// newLoggedEcho returns an Echo instance wired with the request ID, request
+// logging and panic recovery middleware as main does, logging to an
+// in-memory observer.
+func newLoggedEcho() (*echo.Echo, *observer.ObservedLogs) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	log := zap.New(core)
+
+	e := echo.New()
+	e.HTTPErrorHandler = handlers.NewHTTPErrorHandler(zap.NewNop())
+	e.Use(middleware.RequestID())
+	e.Use(middleware.RequestLogger(log))
+	e.Use(middleware.Recover(log))
+	return e, logs
+}
+
+func TestRequestLogger(t *testing.T) {
+	e, logs := newLoggedEcho()
+	e.GET("/accounts/:id", func(c echo.Context) error {
+		switch c.Param("id") {
+		case "missing":
+			return echo.ErrNotFound
+		case "broken":
+			return errors.New("disk on fire")
+		}
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	tests := []struct {
+		path   string
+		status int
+		level  zapcore.Level
+	}{
+		{path: "/accounts/1", status: http.StatusNoContent, level: zapcore.InfoLevel},
+		{path: "/accounts/missing", status: http.StatusNotFound, level: zapcore.WarnLevel},
+		{path: "/accounts/broken", status: http.StatusInternalServerError, level: zapcore.ErrorLevel},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			logs.TakeAll()
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.status)
+			}
+
+			entries := logs.FilterMessage("request").All()
+			if len(entries) != 1 {
+				t.Fatalf("got %d request log lines, want 1", len(entries))
+			}
+			entry := entries[0]
+			if entry.Level != tt.level {
+				t.Errorf("level = %v, want %v", entry.Level, tt.level)
+			}
+			fields := entry.ContextMap()
+			if got := fields["status"]; got != int64(tt.status) {
+				t.Errorf("status field = %v, want %d", got, tt.status)
+			}
+			if got := fields["route"]; got != "/accounts/:id" {
+				t.Errorf("route field = %v, want /accounts/:id", got)
+			}
+			if got, want := fields["request_id"], rec.Header().Get(echo.HeaderXRequestID); got != want {
+				t.Errorf("request_id field = %v, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestRecover(t *testing.T) {
+	e, logs := newLoggedEcho()
+	e.GET("/panic", func(echo.Context) error { panic("nil map write") })
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/panic", nil))
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", rec.Code)
+	}
+
+	panics := logs.FilterMessage("panic recovered").All()
+	if len(panics) != 1 {
+		t.Fatalf("got %d panic log lines, want 1", len(panics))
+	}
+	fields := panics[0].ContextMap()
+	if got, want := fields["request_id"], rec.Header().Get(echo.HeaderXRequestID); got != want {
+		t.Errorf("request_id field = %v, want %q", got, want)
+	}
+	if fields["stack"] == "" {
+		t.Error("panic logged without a stack trace")
+	}
+
+	// The request is still logged, as a server error.
+	requests := logs.FilterMessage("request").All()
+	if len(requests) != 1 || requests[0].Level != zapcore.ErrorLevel {
+		t.Errorf("request log lines = %+v, want one at error level", requests)
+	}
+}
@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"golang.org/x/time/rate"
+
+	"BankSystemGoLang/clock"
+	"BankSystemGoLang/types"
+)
+
+// Limiter decides whether a client may make another request. RateLimiter
+// keeps its buckets in process memory; RedisRateLimiter shares them across
+// instances.
+type Limiter interface {
+	// Allow takes a token from key's bucket. When the bucket is empty it
+	// returns false and how long until a token is available.
+	Allow(ctx context.Context, key string) (ok bool, retryAfter time.Duration, err error)
+}
+
+type bucket struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// RateLimiter keeps one token bucket per client. Each bucket holds up to
+// perMinute tokens and refills at perMinute tokens a minute, so a client
+// may burst up to its whole allowance and is then paced.
+type RateLimiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	limit     rate.Limit
+	burst     int
+	clock     clock.Clock
+	lastSweep time.Time
+}
+
+func NewRateLimiter(perMinute int, clk clock.Clock) *RateLimiter {
+	return &RateLimiter{
+		buckets: make(map[string]*bucket),
+		limit:   rate.Limit(float64(perMinute) / time.Minute.Seconds()),
+		burst:   perMinute,
+		clock:   clk,
+	}
+}
+
+// Allow implements Limiter; it never fails.
+func (l *RateLimiter) Allow(_ context.Context, key string) (bool, time.Duration, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.clock.Now()
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.buckets[key] = b
+	}
+	b.lastSeen = now
+
+	r := b.limiter.ReserveN(now, 1)
+	if delay := r.DelayFrom(now); delay > 0 {
+		r.CancelAt(now)
+		return false, delay, nil
+	}
+	return true, 0, nil
+}
+
+// sweep drops buckets idle long enough to have refilled completely, at
+// most once a minute; l.mu must be held.
+func (l *RateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < time.Minute {
+		return
+	}
+	l.lastSweep = now
+	for key, b := range l.buckets {
+		if now.Sub(b.lastSeen) >= time.Minute {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// RateLimit answers 429 with a Retry-After header once a client has used
+// up its allowance. Clients are the authenticated user when RateLimit runs
+// after RequireAuth, and the client IP otherwise.
+func RateLimit(l Limiter) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			key := "ip:" + c.RealIP()
+			if id := UserID(c); id != 0 {
+				key = "user:" + strconv.FormatInt(id, 10)
+			}
+
+			ok, retryAfter, err := l.Allow(c.Request().Context(), key)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				seconds := int(math.Ceil(retryAfter.Seconds()))
+				c.Response().Header().Set(echo.HeaderRetryAfter, strconv.Itoa(seconds))
+				return c.JSON(http.StatusTooManyRequests, types.ErrorResponse{
+					Code:      types.CodeRateLimited,
+					Error:     "too many requests, retry after " + strconv.Itoa(seconds) + "s",
+					RequestID: RequestIDFrom(c),
+				})
+			}
+			return next(c)
+		}
+	}
+}
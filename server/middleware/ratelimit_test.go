@@ -0,0 +1,66 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"BankSystemGoLang/clock"
+	"BankSystemGoLang/middleware"
+	"BankSystemGoLang/types"
+)
+
+// rateLimitedServer serves GET / through RateLimit with l.
+func rateLimitedServer(l middleware.Limiter) *echo.Echo {
+	e := echo.New()
+	e.GET("/", func(c echo.Context) error { return c.NoContent(http.StatusNoContent) }, middleware.RateLimit(l))
+	return e
+}
+
+func getFrom(e *echo.Echo, ip string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = ip + ":40000"
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRateLimit(t *testing.T) {
+	clk := clock.NewMock(time.Date(2025, time.March, 10, 12, 0, 0, 0, time.UTC))
+	e := rateLimitedServer(middleware.NewRateLimiter(3, clk))
+
+	for i := range 3 {
+		if rec := getFrom(e, "192.0.2.1"); rec.Code != http.StatusNoContent {
+			t.Fatalf("request %d = %d, want it allowed", i+1, rec.Code)
+		}
+	}
+
+	rec := getFrom(e, "192.0.2.1")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("request over the limit = %d, want 429", rec.Code)
+	}
+	// Three a minute refill one token every 20 seconds.
+	if got := rec.Header().Get(echo.HeaderRetryAfter); got != "20" {
+		t.Errorf("Retry-After = %q, want 20", got)
+	}
+	var body types.ErrorResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Code != types.CodeRateLimited {
+		t.Errorf("code = %q, want %q", body.Code, types.CodeRateLimited)
+	}
+
+	if rec := getFrom(e, "192.0.2.2"); rec.Code != http.StatusNoContent {
+		t.Errorf("another client = %d, want its own allowance", rec.Code)
+	}
+
+	clk.Advance(20 * time.Second)
+	if rec := getFrom(e, "192.0.2.1"); rec.Code != http.StatusNoContent {
+		t.Errorf("after Retry-After = %d, want it allowed again", rec.Code)
+	}
+}
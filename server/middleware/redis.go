@@ -0,0 +1,186 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	"BankSystemGoLang/clock"
+)
+
+// Key prefixes keep the rate limiter's and the idempotency store's keys
+// apart from each other and from anything else sharing the Redis database.
+const (
+	redisRateLimitPrefix   = "banksystem:ratelimit:"
+	redisIdempotencyPrefix = "banksystem:idempotency:"
+)
+
+// tokenBucket is the token bucket of RateLimiter run atomically in Redis.
+// It refills rate tokens per millisecond up to burst and returns whether a
+// token was taken and, if not, the milliseconds until one is available.
+// An idle bucket expires once it would have refilled completely.
+var tokenBucket = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1]) or burst
+local ts = tonumber(state[2]) or now
+if now > ts then
+	tokens = math.min(burst, tokens + (now - ts) * rate)
+	ts = now
+end
+
+local allowed, wait = 0, 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	wait = math.ceil((1 - tokens) / rate)
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', ts)
+redis.call('PEXPIRE', KEYS[1], math.ceil(burst / rate))
+return {allowed, wait}
+`)
+
+// RedisRateLimiter is a Limiter whose buckets live in Redis, so every
+// instance behind a load balancer draws on the same allowance. Buckets
+// behave like RateLimiter's. While Redis cannot be reached it limits in
+// process instead and logs a warning, rather than failing every request.
+type RedisRateLimiter struct {
+	client    redis.UniversalClient
+	perMinute int
+	clock     clock.Clock
+	fallback  *RateLimiter
+	log       *zap.Logger
+}
+
+func NewRedisRateLimiter(client redis.UniversalClient, perMinute int, clk clock.Clock, log *zap.Logger) *RedisRateLimiter {
+	return &RedisRateLimiter{
+		client:    client,
+		perMinute: perMinute,
+		clock:     clk,
+		fallback:  NewRateLimiter(perMinute, clk),
+		log:       log,
+	}
+}
+
+func (l *RedisRateLimiter) Allow(ctx context.Context, key string) (bool, time.Duration, error) {
+	perMillisecond := float64(l.perMinute) / float64(time.Minute/time.Millisecond)
+	res, err := tokenBucket.Run(ctx, l.client, []string{redisRateLimitPrefix + key},
+		perMillisecond, l.perMinute, l.clock.Now().UnixMilli()).Int64Slice()
+	if err != nil {
+		l.log.Warn("redis rate limit unavailable; limiting in process", zap.Error(err))
+		return l.fallback.Allow(ctx, key)
+	}
+	if res[0] == 1 {
+		return true, 0, nil
+	}
+	return false, time.Duration(res[1]) * time.Millisecond, nil
+}
+
+// redisIdempotentResult is the JSON value kept in Redis for a key.
+type redisIdempotentResult struct {
+	Fingerprint string          `json:"fingerprint"`
+	Done        bool            `json:"done"`
+	Response    *StoredResponse `json:"response,omitempty"`
+}
+
+// RedisIdempotencyStore is an IdempotencyStore keeping keys in Redis, so a
+// retry reaching another instance is still answered from the first
+// response. Unlike the rate limiter it does not fall back to process
+// memory: a key another instance cannot see would let a retried money
+// movement run twice, so Redis errors fail the request instead.
+type RedisIdempotencyStore struct {
+	client redis.UniversalClient
+	ttl    time.Duration
+}
+
+func NewRedisIdempotencyStore(client redis.UniversalClient, ttl time.Duration) *RedisIdempotencyStore {
+	return &RedisIdempotencyStore{client: client, ttl: ttl}
+}
+
+func (s *RedisIdempotencyStore) Begin(ctx context.Context, key, fingerprint string) (*StoredResponse, error) {
+	reserved, err := json.Marshal(redisIdempotentResult{Fingerprint: fingerprint})
+	if err != nil {
+		return nil, err
+	}
+
+	// The key can expire between a failed SET NX and the GET; trying once
+	// more then reserves it.
+	for range 2 {
+		ok, err := s.client.SetNX(ctx, redisIdempotencyPrefix+key, reserved, s.ttl).Result()
+		if err != nil {
+			return nil, fmt.Errorf("reserve idempotency key: %w", err)
+		}
+		if ok {
+			return nil, nil
+		}
+
+		raw, err := s.client.Get(ctx, redisIdempotencyPrefix+key).Bytes()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read idempotency key: %w", err)
+		}
+		var r redisIdempotentResult
+		if err := json.Unmarshal(raw, &r); err != nil {
+			return nil, fmt.Errorf("read idempotency key: %w", err)
+		}
+		switch {
+		case r.Fingerprint != fingerprint:
+			return nil, errKeyReused
+		case !r.Done || r.Response == nil:
+			return nil, errKeyInFlight
+		}
+		return r.Response, nil
+	}
+	return nil, errKeyInFlight
+}
+
+func (s *RedisIdempotencyStore) Complete(ctx context.Context, key, fingerprint string, res StoredResponse) error {
+	done, err := json.Marshal(redisIdempotentResult{Fingerprint: fingerprint, Done: true, Response: &res})
+	if err != nil {
+		return err
+	}
+	// KEEPTTL keeps the expiry set when the key was reserved, and XX skips
+	// a key that has expired meanwhile rather than storing it forever.
+	err = s.client.SetArgs(ctx, redisIdempotencyPrefix+key, done, redis.SetArgs{Mode: "XX", KeepTTL: true}).Err()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return fmt.Errorf("store idempotent response: %w", err)
+	}
+	return nil
+}
+
+func (s *RedisIdempotencyStore) Release(ctx context.Context, key string) error {
+	if err := s.client.Del(ctx, redisIdempotencyPrefix+key).Err(); err != nil {
+		return fmt.Errorf("release idempotency key: %w", err)
+	}
+	return nil
+}
+
+// ConnectRedis connects to the Redis server at url, a redis:// or
+// rediss:// URL, and checks that it answers within timeout.
+func ConnectRedis(ctx context.Context, url string, timeout time.Duration) (*redis.Client, error) {
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, err
+	}
+	client := redis.NewClient(opts)
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, err
+	}
+	return client, nil
+}
@@ -0,0 +1,77 @@
+package middleware_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	"BankSystemGoLang/clock"
+	"BankSystemGoLang/middleware"
+)
+
+// redisLimitedServers returns two servers rate limited through separate
+// clients of the same Redis, as two instances behind a load balancer are.
+func redisLimitedServers(t *testing.T, addr string, clk clock.Clock) (*echo.Echo, *echo.Echo) {
+	t.Helper()
+	servers := make([]*echo.Echo, 2)
+	for i := range servers {
+		client := redis.NewClient(&redis.Options{Addr: addr, MaxRetries: -1, DialerRetries: 1, DialTimeout: 100 * time.Millisecond})
+		t.Cleanup(func() { client.Close() })
+		servers[i] = rateLimitedServer(middleware.NewRedisRateLimiter(client, 3, clk, zap.NewNop()))
+	}
+	return servers[0], servers[1]
+}
+
+func TestRedisRateLimitIsShared(t *testing.T) {
+	mr := miniredis.RunT(t)
+	clk := clock.NewMock(time.Date(2025, time.March, 10, 12, 0, 0, 0, time.UTC))
+	first, second := redisLimitedServers(t, mr.Addr(), clk)
+
+	for i, e := range []*echo.Echo{first, second, first} {
+		if rec := getFrom(e, "192.0.2.1"); rec.Code != http.StatusNoContent {
+			t.Fatalf("request %d = %d, want it allowed", i+1, rec.Code)
+		}
+	}
+	rec := getFrom(second, "192.0.2.1")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("fourth request on the other instance = %d, want 429", rec.Code)
+	}
+	if got := rec.Header().Get(echo.HeaderRetryAfter); got != "20" {
+		t.Errorf("Retry-After = %q, want 20", got)
+	}
+	if rec := getFrom(first, "192.0.2.2"); rec.Code != http.StatusNoContent {
+		t.Errorf("another client = %d, want its own allowance", rec.Code)
+	}
+
+	clk.Advance(20 * time.Second)
+	if rec := getFrom(first, "192.0.2.1"); rec.Code != http.StatusNoContent {
+		t.Errorf("after 20s = %d, want one token refilled", rec.Code)
+	}
+	if rec := getFrom(second, "192.0.2.1"); rec.Code != http.StatusTooManyRequests {
+		t.Errorf("second request after 20s = %d, want only one token refilled", rec.Code)
+	}
+}
+
+func TestRedisRateLimitFallsBackInProcess(t *testing.T) {
+	mr := miniredis.RunT(t)
+	clk := clock.NewMock(time.Date(2025, time.March, 10, 12, 0, 0, 0, time.UTC))
+	first, second := redisLimitedServers(t, mr.Addr(), clk)
+	mr.Close()
+
+	// Each instance now limits on its own rather than failing requests.
+	for _, e := range []*echo.Echo{first, second} {
+		for i := range 3 {
+			if rec := getFrom(e, "192.0.2.1"); rec.Code != http.StatusNoContent {
+				t.Fatalf("request %d without Redis = %d, want it allowed", i+1, rec.Code)
+			}
+		}
+		if rec := getFrom(e, "192.0.2.1"); rec.Code != http.StatusTooManyRequests {
+			t.Errorf("request over the in-process limit = %d, want 429", rec.Code)
+		}
+	}
+}
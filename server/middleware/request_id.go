@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	requestIDKey       = "request_id"
+	maxRequestIDLength = 128
+)
+
+// RequestID tags every request with a correlation ID. An incoming
+// X-Request-ID is reused when it looks sane; otherwise a UUID is generated.
+// The ID is echoed back in the X-Request-ID response header.
+func RequestID() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			id := c.Request().Header.Get(echo.HeaderXRequestID)
+			if !validRequestID(id) {
+				id = uuid.NewString()
+				c.Request().Header.Set(echo.HeaderXRequestID, id)
+			}
+
+			c.Set(requestIDKey, id)
+			c.Response().Header().Set(echo.HeaderXRequestID, id)
+			return next(c)
+		}
+	}
+}
+
+// RequestIDFrom returns the correlation ID assigned by RequestID.
+func RequestIDFrom(c echo.Context) string {
+	id, _ := c.Get(requestIDKey).(string)
+	return id
+}
+
+// validRequestID rejects empty, oversized or non-printable IDs so a client
+// cannot inject garbage into the logs.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,74 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"BankSystemGoLang/middleware"
+)
+
+func TestRequestID(t *testing.T) {
+	e := echo.New()
+	e.Use(middleware.RequestID())
+	var seen string
+	e.GET("/accounts", func(c echo.Context) error {
+		seen = middleware.RequestIDFrom(c)
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	tests := []struct {
+		name     string
+		incoming string
+		keep     bool
+	}{
+		{name: "no incoming ID"},
+		{name: "sane incoming ID", incoming: "req-42.retry_1", keep: true},
+		{name: "incoming ID with a space", incoming: "req 42"},
+		{name: "incoming ID with a newline", incoming: "req-42\nlevel=error"},
+		{name: "oversized incoming ID", incoming: strings.Repeat("a", 129)},
+		{name: "longest allowed incoming ID", incoming: strings.Repeat("a", 128), keep: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seen = ""
+			req := httptest.NewRequest(http.MethodGet, "/accounts", nil)
+			if tt.incoming != "" {
+				req.Header.Set(echo.HeaderXRequestID, tt.incoming)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			got := rec.Header().Get(echo.HeaderXRequestID)
+			if got == "" {
+				t.Fatal("response has no X-Request-ID")
+			}
+			if seen != got {
+				t.Errorf("RequestIDFrom = %q, want the response header %q", seen, got)
+			}
+			if keep := got == tt.incoming; keep != tt.keep {
+				t.Errorf("X-Request-ID = %q for incoming %q, want kept = %v", got, tt.incoming, tt.keep)
+			}
+		})
+	}
+}
+
+func TestRequestIDIsUniquePerRequest(t *testing.T) {
+	e := echo.New()
+	e.Use(middleware.RequestID())
+	e.GET("/accounts", func(c echo.Context) error { return c.NoContent(http.StatusNoContent) })
+
+	seen := map[string]bool{}
+	for range 100 {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/accounts", nil))
+		id := rec.Header().Get(echo.HeaderXRequestID)
+		if seen[id] {
+			t.Fatalf("request ID %q handed out twice", id)
+		}
+		seen[id] = true
+	}
+}
@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Timeout gives each request's context a deadline timeout from now. The
+// context is also cancelled when the client disconnects, so store calls,
+// which stop once their context is done, cannot outlive either. Handlers
+// must pass c.Request().Context() on to the services they call.
+func Timeout(timeout time.Duration) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx, cancel := context.WithTimeout(c.Request().Context(), timeout)
+			defer cancel()
+			c.SetRequest(c.Request().WithContext(ctx))
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	_ "modernc.org/sqlite"
+
+	"BankSystemGoLang/migrations"
+)
+
+func TestUpIsIdempotent(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "bank.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	all, err := migrations.All()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) == 0 {
+		t.Fatal("no embedded migrations")
+	}
+
+	applied, err := migrations.Up(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != len(all) {
+		t.Fatalf("first Up applied %d migrations, want all %d", len(applied), len(all))
+	}
+
+	applied, err = migrations.Up(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != 0 {
+		t.Errorf("second Up applied %v, want nothing", applied)
+	}
+
+	var recorded int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM schema_migrations`).Scan(&recorded); err != nil {
+		t.Fatal(err)
+	}
+	if recorded != len(all) {
+		t.Errorf("schema_migrations has %d rows, want %d", recorded, len(all))
+	}
+}
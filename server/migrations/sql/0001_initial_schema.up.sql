@@ -0,0 +1,67 @@
+-- The tables existed before versioned migrations were introduced, so this
+-- migration keeps IF NOT EXISTS to adopt those databases as version 1.
+-- Money columns hold integer cents (types.Money).
+
+CREATE TABLE IF NOT EXISTS accounts (
+	id              INTEGER PRIMARY KEY AUTOINCREMENT,
+	user_id         INTEGER  NOT NULL,
+	owner_name      TEXT     NOT NULL,
+	email           TEXT     NOT NULL UNIQUE COLLATE NOCASE,
+	account_type    TEXT     NOT NULL DEFAULT 'checking',
+	currency        TEXT     NOT NULL DEFAULT 'USD',
+	balance         INTEGER  NOT NULL DEFAULT 0,
+	overdraft_limit INTEGER  NOT NULL DEFAULT 0,
+	status          TEXT     NOT NULL DEFAULT 'open',
+	created_at      DATETIME NOT NULL,
+	closed_at       DATETIME,
+	last_accrued_at DATETIME
+);
+
+CREATE TABLE IF NOT EXISTS transactions (
+	id               INTEGER PRIMARY KEY AUTOINCREMENT,
+	account_id       INTEGER  NOT NULL REFERENCES accounts(id),
+	type             TEXT     NOT NULL,
+	amount           INTEGER  NOT NULL,
+	balance_after    INTEGER  NOT NULL,
+	exchange_rate    TEXT     NOT NULL DEFAULT '',
+	converted_amount INTEGER,
+	created_at       DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_transactions_account ON transactions (account_id, id);
+
+CREATE TABLE IF NOT EXISTS scheduled_transfers (
+	id          INTEGER PRIMARY KEY AUTOINCREMENT,
+	user_id     INTEGER  NOT NULL,
+	from_id     INTEGER  NOT NULL REFERENCES accounts(id),
+	to_id       INTEGER  NOT NULL REFERENCES accounts(id),
+	amount      INTEGER  NOT NULL,
+	frequency   TEXT     NOT NULL,
+	start_at    DATETIME NOT NULL,
+	next_run_at DATETIME NOT NULL,
+	runs        INTEGER  NOT NULL DEFAULT 0,
+	last_run_at DATETIME,
+	last_error  TEXT     NOT NULL DEFAULT '',
+	created_at  DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_scheduled_transfers_next_run ON scheduled_transfers (next_run_at);
+
+CREATE TABLE IF NOT EXISTS webhooks (
+	id         INTEGER PRIMARY KEY AUTOINCREMENT,
+	user_id    INTEGER  NOT NULL,
+	url        TEXT     NOT NULL,
+	events     TEXT     NOT NULL, -- comma-separated event types
+	secret     TEXT     NOT NULL,
+	created_at DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhooks_user ON webhooks (user_id);
+
+CREATE TABLE IF NOT EXISTS users (
+	id            INTEGER PRIMARY KEY AUTOINCREMENT,
+	email         TEXT     NOT NULL UNIQUE COLLATE NOCASE,
+	password_hash TEXT     NOT NULL,
+	role          TEXT     NOT NULL DEFAULT 'user',
+	created_at    DATETIME NOT NULL
+);
@@ -0,0 +1,3 @@
+DROP TABLE holds;
+
+ALTER TABLE accounts DROP COLUMN held;
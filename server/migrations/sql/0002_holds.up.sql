@@ -0,0 +1,15 @@
+-- Authorization holds. accounts.held is the total of the account's active
+-- holds, kept in step with the holds table by the store.
+
+ALTER TABLE accounts ADD COLUMN held INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE holds (
+	id          INTEGER PRIMARY KEY AUTOINCREMENT,
+	account_id  INTEGER  NOT NULL REFERENCES accounts(id),
+	amount      INTEGER  NOT NULL,
+	status      TEXT     NOT NULL DEFAULT 'active',
+	created_at  DATETIME NOT NULL,
+	resolved_at DATETIME
+);
+
+CREATE INDEX idx_holds_account ON holds (account_id);
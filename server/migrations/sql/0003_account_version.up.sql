@@ -0,0 +1,4 @@
+-- Optimistic concurrency: every change to an account increments its
+-- version, and updates only apply while the version is the one read.
+
+ALTER TABLE accounts ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
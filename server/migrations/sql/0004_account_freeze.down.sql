@@ -0,0 +1,3 @@
+ALTER TABLE transactions DROP COLUMN reason;
+
+ALTER TABLE accounts DROP COLUMN frozen;
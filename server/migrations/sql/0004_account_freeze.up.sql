@@ -0,0 +1,7 @@
+-- Support staff can freeze an account to stop money moving while fraud is
+-- investigated. Freezing and unfreezing are recorded in the ledger with the
+-- reason given.
+
+ALTER TABLE accounts ADD COLUMN frozen INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE transactions ADD COLUMN reason TEXT NOT NULL DEFAULT '';
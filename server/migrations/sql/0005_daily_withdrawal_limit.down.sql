@@ -0,0 +1 @@
+ALTER TABLE accounts DROP COLUMN daily_withdrawal_limit;
@@ -0,0 +1,4 @@
+-- Per-account cap on the total withdrawn each UTC day. Existing accounts
+-- get services.DefaultDailyWithdrawalLimit (5000.00).
+
+ALTER TABLE accounts ADD COLUMN daily_withdrawal_limit INTEGER NOT NULL DEFAULT 500000;
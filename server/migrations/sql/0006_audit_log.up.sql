@@ -0,0 +1,23 @@
+-- Every admin action that changed an account is recorded here for
+-- compliance. Entries are never changed or removed once written.
+
+CREATE TABLE audit_log (
+	id         INTEGER PRIMARY KEY AUTOINCREMENT,
+	admin_id   INTEGER  NOT NULL REFERENCES users(id),
+	action     TEXT     NOT NULL,
+	account_id INTEGER  NOT NULL REFERENCES accounts(id),
+	request    TEXT     NOT NULL DEFAULT '',
+	created_at DATETIME NOT NULL
+);
+
+CREATE INDEX idx_audit_log_created_at ON audit_log (created_at);
+
+CREATE TRIGGER audit_log_no_update BEFORE UPDATE ON audit_log
+BEGIN
+	SELECT RAISE(ABORT, 'audit_log is append-only');
+END;
+
+CREATE TRIGGER audit_log_no_delete BEFORE DELETE ON audit_log
+BEGIN
+	SELECT RAISE(ABORT, 'audit_log is append-only');
+END;
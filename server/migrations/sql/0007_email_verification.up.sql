@@ -0,0 +1,13 @@
+-- New users must verify their email before they can log in. Users created
+-- before verification existed are treated as verified.
+
+ALTER TABLE users ADD COLUMN verified_at DATETIME;
+UPDATE users SET verified_at = created_at;
+
+-- Only the SHA-256 hash of each token is kept, and a token is deleted once
+-- used.
+CREATE TABLE email_verifications (
+	token_hash TEXT     PRIMARY KEY,
+	user_id    INTEGER  NOT NULL REFERENCES users(id),
+	expires_at DATETIME NOT NULL
+);
@@ -0,0 +1,30 @@
+package openapi
+
+import (
+	"fmt"
+	"html"
+)
+
+// swaggerUIVersion pins the Swagger UI bundle the docs page loads.
+const swaggerUIVersion = "5.17.14"
+
+// DocsPage returns a Swagger UI page that renders the document served at
+// specURL. The UI assets come from a CDN so nothing is vendored.
+func DocsPage(title, specURL string) []byte {
+	return fmt.Appendf(nil, `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>%[1]s</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@%[2]s/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@%[2]s/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.ui = SwaggerUIBundle({ url: %[3]q, dom_id: "#swagger-ui" });
+  </script>
+</body>
+</html>
+`, html.EscapeString(title), swaggerUIVersion, specURL)
+}
@@ -0,0 +1,226 @@
+// Package openapi builds the OpenAPI 3 description of the API. Request and
+// response schemas are generated from the types package by reflection, so
+// the document cannot drift from the DTOs the handlers actually bind and
+// return; only the list of operations is written by hand.
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"BankSystemGoLang/types"
+)
+
+// Version is the OpenAPI version of the generated document.
+const Version = "3.0.3"
+
+// Info describes the API as a whole.
+type Info struct {
+	Title       string
+	Version     string
+	Description string
+}
+
+// Operation documents one route. Path uses Echo's syntax, e.g.
+// /accounts/:id; path parameters are derived from it.
+type Operation struct {
+	Method      string
+	Path        string
+	Summary     string
+	Description string
+	Tag         string
+	// Auth marks routes that need a bearer token.
+	Auth      bool
+	Query     []Param
+	Headers   []Param
+	Request   any // a value of the body type, or nil for no body
+	Responses []Response
+}
+
+// Param is a query or header parameter. Type defaults to string.
+type Param struct {
+	Name        string
+	Description string
+	Type        string
+	Format      string
+	Enum        []string
+	Required    bool
+}
+
+// Response documents one status an operation can return. Body is a value
+// of the JSON body type; ContentTypes instead lists non-JSON formats.
+type Response struct {
+	Status       int
+	Description  string
+	Body         any
+	ContentTypes []string
+	Headers      []Param
+}
+
+// Build renders the document for ops. Every operation also gets a default
+// response with the ErrorResponse shape every handler uses for failures.
+func Build(info Info, ops []Operation) ([]byte, error) {
+	components := schemas{}
+	errorBody := jsonContent(components.ref(reflect.TypeFor[types.ErrorResponse]()))
+
+	paths := map[string]map[string]any{}
+	for _, op := range ops {
+		path, params := pathParams(op.Path)
+		for _, p := range op.Query {
+			params = append(params, param("query", p))
+		}
+		for _, p := range op.Headers {
+			params = append(params, param("header", p))
+		}
+
+		responses := map[string]any{
+			"default": map[string]any{"description": "Error", "content": errorBody},
+		}
+		for _, r := range op.Responses {
+			responses[strconv.Itoa(r.Status)] = components.response(r)
+		}
+
+		operation := map[string]any{
+			"summary":     op.Summary,
+			"operationId": operationID(op),
+			"responses":   responses,
+		}
+		if op.Description != "" {
+			operation["description"] = op.Description
+		}
+		if op.Tag != "" {
+			operation["tags"] = []string{op.Tag}
+		}
+		if len(params) > 0 {
+			operation["parameters"] = params
+		}
+		if op.Request != nil {
+			operation["requestBody"] = map[string]any{
+				"required": true,
+				"content":  jsonContent(components.ref(reflect.TypeOf(op.Request))),
+			}
+		}
+		if op.Auth {
+			operation["security"] = []map[string][]string{{"bearerAuth": {}}}
+		}
+
+		if paths[path] == nil {
+			paths[path] = map[string]any{}
+		}
+		paths[path][strings.ToLower(op.Method)] = operation
+	}
+
+	doc := map[string]any{
+		"openapi": Version,
+		"info": map[string]any{
+			"title":       info.Title,
+			"version":     info.Version,
+			"description": info.Description,
+		},
+		"paths": paths,
+		"components": map[string]any{
+			"schemas": components,
+			"securitySchemes": map[string]any{
+				"bearerAuth": map[string]any{"type": "http", "scheme": "bearer", "bearerFormat": "JWT"},
+			},
+		},
+	}
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+func (s schemas) response(r Response) map[string]any {
+	description := r.Description
+	if description == "" {
+		description = http.StatusText(r.Status)
+	}
+	response := map[string]any{"description": description}
+
+	switch {
+	case r.Body != nil:
+		response["content"] = jsonContent(s.ref(reflect.TypeOf(r.Body)))
+	case len(r.ContentTypes) > 0:
+		content := map[string]any{}
+		for _, ct := range r.ContentTypes {
+			content[ct] = map[string]any{"schema": map[string]any{"type": "string", "format": "binary"}}
+		}
+		response["content"] = content
+	}
+
+	if len(r.Headers) > 0 {
+		headers := map[string]any{}
+		for _, h := range r.Headers {
+			headers[h.Name] = map[string]any{"description": h.Description, "schema": paramSchema(h)}
+		}
+		response["headers"] = headers
+	}
+	return response
+}
+
+// pathParams turns /accounts/:id into /accounts/{id} and returns the
+// parameters it names. Every path parameter in this API is a numeric ID.
+func pathParams(path string) (string, []any) {
+	segments := strings.Split(path, "/")
+	var params []any
+	for i, segment := range segments {
+		name, ok := strings.CutPrefix(segment, ":")
+		if !ok {
+			continue
+		}
+		segments[i] = "{" + name + "}"
+		params = append(params, map[string]any{
+			"name":     name,
+			"in":       "path",
+			"required": true,
+			"schema":   map[string]any{"type": "integer", "format": "int64", "minimum": 1},
+		})
+	}
+	return strings.Join(segments, "/"), params
+}
+
+func param(in string, p Param) map[string]any {
+	param := map[string]any{
+		"name":   p.Name,
+		"in":     in,
+		"schema": paramSchema(p),
+	}
+	if p.Description != "" {
+		param["description"] = p.Description
+	}
+	if p.Required {
+		param["required"] = true
+	}
+	return param
+}
+
+func paramSchema(p Param) map[string]any {
+	schema := map[string]any{"type": "string"}
+	if p.Type != "" {
+		schema["type"] = p.Type
+	}
+	if p.Format != "" {
+		schema["format"] = p.Format
+	}
+	if len(p.Enum) > 0 {
+		schema["enum"] = p.Enum
+	}
+	return schema
+}
+
+func jsonContent(schema map[string]any) map[string]any {
+	return map[string]any{"application/json": map[string]any{"schema": schema}}
+}
+
+// operationID derives a stable identifier such as postAccountsIdDeposit.
+func operationID(op Operation) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(op.Method))
+	for _, word := range strings.FieldsFunc(op.Path, func(r rune) bool {
+		return r == '/' || r == ':' || r == '-'
+	}) {
+		b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	return b.String()
+}
@@ -0,0 +1,146 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"BankSystemGoLang/types"
+)
+
+var (
+	timeType  = reflect.TypeFor[time.Time]()
+	moneyType = reflect.TypeFor[types.Money]()
+	rawType   = reflect.TypeFor[json.RawMessage]()
+)
+
+// derived lists JSON properties a type adds in a custom MarshalJSON, which
+// reflection cannot see.
+var derived = map[reflect.Type]map[string]any{
+	reflect.TypeFor[types.Account](): {"available": moneySchema("Balance minus held.")},
+}
+
+// schemas collects the component schemas of every struct type reached from
+// the operations, keyed by Go type name.
+type schemas map[string]any
+
+// ref returns the schema of t, registering struct types as components and
+// referring to them by name so shared DTOs are described once.
+func (s schemas) ref(t reflect.Type) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch {
+	case t == timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case t == moneyType:
+		return moneySchema("")
+	case t == rawType:
+		return map[string]any{} // any JSON value
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if _, ok := s[t.Name()]; !ok {
+			s[t.Name()] = nil // reserve the name before recursing
+			s[t.Name()] = s.object(t)
+		}
+		return map[string]any{"$ref": "#/components/schemas/" + t.Name()}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": s.ref(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": s.ref(t.Elem())}
+	case reflect.Interface:
+		return map[string]any{} // any JSON value
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer", "format": "int64"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	}
+	return map[string]any{"type": "string"}
+}
+
+// object describes a struct from its json and validate tags.
+func (s schemas) object(t reflect.Type) map[string]any {
+	properties := map[string]any{}
+	var required []string
+	for i := range t.NumField() {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if !f.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		schema := s.ref(f.Type)
+		if constrain(schema, f) {
+			required = append(required, name)
+		}
+		properties[name] = schema
+	}
+	for name, schema := range derived[t] {
+		properties[name] = schema
+	}
+
+	object := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		object["required"] = required
+	}
+	return object
+}
+
+// constrain adds the field's validate rules to its schema and reports
+// whether the field is required. Rules after a dive apply to the elements.
+func constrain(schema map[string]any, f reflect.StructField) (required bool) {
+	target, dived := schema, false
+	for rule := range strings.SplitSeq(f.Tag.Get("validate"), ",") {
+		tag, param, _ := strings.Cut(rule, "=")
+		switch tag {
+		case "required":
+			required = required || !dived
+		case "dive":
+			if items, ok := schema["items"].(map[string]any); ok {
+				target, dived = items, true
+			}
+		case "oneof":
+			target["enum"] = strings.Fields(param)
+		case "email":
+			target["format"] = "email"
+		case "http_url":
+			target["format"] = "uri"
+		case "iso4217":
+			target["pattern"] = "^[A-Z]{3}$"
+		case "gt", "gte":
+			if n, err := strconv.Atoi(param); err == nil && target["type"] == "integer" {
+				target["minimum"] = n
+				if tag == "gt" {
+					target["exclusiveMinimum"] = true
+				}
+			}
+		case "min", "max":
+			if n, err := strconv.Atoi(param); err == nil && target["type"] == "array" {
+				target[tag+"Items"] = n
+			}
+		}
+	}
+	return required
+}
+
+func moneySchema(description string) map[string]any {
+	schema := map[string]any{
+		"type":    "string",
+		"format":  "decimal",
+		"example": "100.50",
+	}
+	if description != "" {
+		schema["description"] = description
+	}
+	return schema
+}
@@ -0,0 +1,265 @@
+package route
+
+import (
+	"net/http"
+
+	"BankSystemGoLang/middleware"
+	"BankSystemGoLang/openapi"
+	"BankSystemGoLang/types"
+)
+
+// SpecPath is where the OpenAPI document is served; DocsPath renders it.
+const (
+	SpecPath = "/openapi.json"
+	DocsPath = "/docs"
+)
+
+var info = openapi.Info{
+	Title:   "Bank System API",
+	Version: "1.0.0",
+	Description: "Accounts, transfers and statements. Money amounts are decimal strings such as \"100.50\". " +
+		"Authenticate with the token from POST /login as a bearer token.",
+}
+
+// OpenAPI builds the document describing every route Register wires, other
+// than the docs routes themselves.
+func OpenAPI() ([]byte, error) {
+	return openapi.Build(info, operations())
+}
+
+// DocsPage returns the Swagger UI page served at DocsPath.
+func DocsPage() []byte {
+	return openapi.DocsPage(info.Title, SpecPath)
+}
+
+func operations() []openapi.Operation {
+	idempotencyKey := openapi.Param{
+		Name:        middleware.HeaderIdempotencyKey,
+		Description: "Replays the stored response when a request with the same key is retried.",
+	}
+	ifMatch := openapi.Param{
+		Name:        middleware.HeaderIfMatch,
+		Description: "Account version from a previous ETag; the request fails with 409 if the account has changed since.",
+	}
+	etag := openapi.Param{Name: middleware.HeaderETag, Description: "Current account version."}
+	pagination := []openapi.Param{
+		{Name: "limit", Type: "integer", Description: "Maximum number of items to return."},
+		{Name: "offset", Type: "integer", Description: "Number of items to skip."},
+	}
+
+	return []openapi.Operation{
+		{
+			Method: http.MethodGet, Path: "/health", Tag: "system",
+			Summary: "Report whether the server and its database are up",
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: types.HealthResponse{}},
+				{Status: http.StatusServiceUnavailable, Description: "The database is unreachable", Body: types.HealthResponse{}},
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/metrics", Tag: "system",
+			Summary:   "Prometheus metrics",
+			Responses: []openapi.Response{{Status: http.StatusOK, ContentTypes: []string{"text/plain"}}},
+		},
+		{
+			Method: http.MethodPost, Path: "/register", Tag: "auth",
+			Summary:   "Create a user and email them a verification link",
+			Request:   types.RegisterRequest{},
+			Responses: []openapi.Response{{Status: http.StatusCreated, Body: types.User{}}},
+		},
+		{
+			Method: http.MethodPost, Path: "/login", Tag: "auth",
+			Summary:     "Exchange credentials for an access token",
+			Description: "Fails with 403 EMAIL_NOT_VERIFIED until the user has followed the link emailed at registration.",
+			Request:     types.LoginRequest{},
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: types.LoginResponse{}},
+				{Status: http.StatusForbidden, Description: "The email address has not been verified", Body: types.ErrorResponse{}},
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/verify", Tag: "auth",
+			Summary:     "Verify a user's email address",
+			Description: "The link emailed at registration. Tokens are single-use and expire after 24 hours.",
+			Query:       []openapi.Param{{Name: "token", Required: true, Description: "Token from the emailed link."}},
+			Responses:   []openapi.Response{{Status: http.StatusOK, Body: types.User{}}},
+		},
+		{
+			Method: http.MethodPost, Path: "/verify/resend", Tag: "auth",
+			Summary:     "Email a new verification link",
+			Description: "Answers 202 whether or not the address belongs to an unverified user.",
+			Request:     types.ResendVerificationRequest{},
+			Responses:   []openapi.Response{{Status: http.StatusAccepted}},
+		},
+		{
+			Method: http.MethodPost, Path: "/accounts", Tag: "accounts", Auth: true,
+			Summary:   "Open an account",
+			Request:   types.CreateAccountRequest{},
+			Responses: []openapi.Response{{Status: http.StatusCreated, Body: types.Account{}}},
+		},
+		{
+			Method: http.MethodGet, Path: "/accounts", Tag: "accounts", Auth: true,
+			Summary: "List the caller's accounts",
+			Query: append(pagination, openapi.Param{
+				Name: "status", Enum: []string{string(types.AccountOpen), string(types.AccountClosed)},
+			}),
+			Responses: []openapi.Response{{Status: http.StatusOK, Body: types.AccountPage{}}},
+		},
+		{
+			Method: http.MethodGet, Path: "/admin/accounts", Tag: "accounts", Auth: true,
+			Summary: "List every user's accounts (admin only)",
+			Query: append(pagination,
+				openapi.Param{Name: "status", Enum: []string{string(types.AccountOpen), string(types.AccountClosed)}},
+				openapi.Param{Name: "user_id", Type: "integer", Description: "Only list this user's accounts."},
+			),
+			Responses: []openapi.Response{{Status: http.StatusOK, Body: types.AccountPage{}}},
+		},
+		{
+			Method: http.MethodGet, Path: "/accounts/:id", Tag: "accounts", Auth: true,
+			Summary:   "Get an account",
+			Responses: []openapi.Response{{Status: http.StatusOK, Body: types.Account{}, Headers: []openapi.Param{etag}}},
+		},
+		{
+			Method: http.MethodPatch, Path: "/accounts/:id", Tag: "accounts", Auth: true,
+			Summary:     "Update an account's contact details",
+			Description: "Only owner_name and email can be changed; fields left out keep their value and any other field is rejected.",
+			Headers:     []openapi.Param{ifMatch},
+			Request:     types.UpdateAccountRequest{},
+			Responses:   []openapi.Response{{Status: http.StatusOK, Body: types.Account{}, Headers: []openapi.Param{etag}}},
+		},
+		{
+			Method: http.MethodDelete, Path: "/accounts/:id", Tag: "accounts", Auth: true,
+			Summary:     "Close an account",
+			Description: "The balance must be zero and no holds may be active. Admins may close any user's account.",
+			Headers:     []openapi.Param{ifMatch},
+			Responses:   []openapi.Response{{Status: http.StatusOK, Body: types.Account{}, Headers: []openapi.Param{etag}}},
+		},
+		{
+			Method: http.MethodPost, Path: "/accounts/:id/deposit", Tag: "accounts", Auth: true,
+			Summary:   "Deposit money",
+			Headers:   []openapi.Param{idempotencyKey, ifMatch},
+			Request:   types.AmountRequest{},
+			Responses: []openapi.Response{{Status: http.StatusOK, Body: types.BalanceResponse{}, Headers: []openapi.Param{etag}}},
+		},
+		{
+			Method: http.MethodPost, Path: "/accounts/:id/withdraw", Tag: "accounts", Auth: true,
+			Summary:     "Withdraw money",
+			Description: "Fails with 422 DAILY_LIMIT_EXCEEDED, reporting what remains, once the day's withdrawals (UTC) would pass the account's daily_withdrawal_limit.",
+			Headers:     []openapi.Param{idempotencyKey, ifMatch},
+			Request:     types.AmountRequest{},
+			Responses:   []openapi.Response{{Status: http.StatusOK, Body: types.BalanceResponse{}, Headers: []openapi.Param{etag}}},
+		},
+		{
+			Method: http.MethodGet, Path: "/accounts/:id/transactions", Tag: "accounts", Auth: true,
+			Summary: "List an account's ledger entries, newest first",
+			Query: append(pagination, openapi.Param{
+				Name:        "cursor",
+				Description: "next_cursor from the previous page; resumes after it and cannot be combined with offset.",
+			}),
+			Responses: []openapi.Response{{Status: http.StatusOK, Body: types.TransactionPage{}}},
+		},
+		{
+			Method: http.MethodGet, Path: "/accounts/:id/statement", Tag: "accounts", Auth: true,
+			Summary:     "Download a statement",
+			Description: "from and to are inclusive and default to the last 30 days.",
+			Query: []openapi.Param{
+				{Name: "from", Format: "date", Description: "First day, YYYY-MM-DD."},
+				{Name: "to", Format: "date", Description: "Last day, YYYY-MM-DD."},
+				{Name: "format", Enum: []string{"csv", "pdf"}, Description: "Defaults to csv."},
+			},
+			Responses: []openapi.Response{{Status: http.StatusOK, ContentTypes: []string{"text/csv", "application/pdf"}}},
+		},
+		{
+			Method: http.MethodPost, Path: "/accounts/:id/accrue-interest", Tag: "accounts", Auth: true,
+			Summary:   "Credit interest on a savings account (admin only)",
+			Responses: []openapi.Response{{Status: http.StatusOK, Body: types.InterestResponse{}}},
+		},
+		{
+			Method: http.MethodPost, Path: "/accounts/:id/freeze", Tag: "accounts", Auth: true,
+			Summary:     "Freeze an account (admin only)",
+			Description: "Money movements on a frozen account fail with 423 Locked until it is unfrozen; reads still work.",
+			Request:     types.FreezeRequest{},
+			Responses:   []openapi.Response{{Status: http.StatusOK, Body: types.Account{}, Headers: []openapi.Param{etag}}},
+		},
+		{
+			Method: http.MethodPost, Path: "/accounts/:id/unfreeze", Tag: "accounts", Auth: true,
+			Summary:   "Lift a freeze (admin only)",
+			Request:   types.FreezeRequest{},
+			Responses: []openapi.Response{{Status: http.StatusOK, Body: types.Account{}, Headers: []openapi.Param{etag}}},
+		},
+		{
+			Method: http.MethodPost, Path: "/accounts/:id/holds", Tag: "holds", Auth: true,
+			Summary:   "Reserve funds on an account",
+			Headers:   []openapi.Param{idempotencyKey},
+			Request:   types.HoldRequest{},
+			Responses: []openapi.Response{{Status: http.StatusCreated, Body: types.Hold{}}},
+		},
+		{
+			Method: http.MethodPost, Path: "/holds/:id/capture", Tag: "holds", Auth: true,
+			Summary:   "Withdraw the held funds",
+			Headers:   []openapi.Param{idempotencyKey},
+			Responses: []openapi.Response{{Status: http.StatusOK, Body: types.Hold{}}},
+		},
+		{
+			Method: http.MethodPost, Path: "/holds/:id/release", Tag: "holds", Auth: true,
+			Summary:   "Return the held funds to the available balance",
+			Responses: []openapi.Response{{Status: http.StatusOK, Body: types.Hold{}}},
+		},
+		{
+			Method: http.MethodPost, Path: "/transfers", Tag: "transfers", Auth: true,
+			Summary:     "Move money between accounts",
+			Description: "A dry run validates the transfer and reports the balances and conversion it would produce, failing as the transfer would, without moving any money.",
+			Headers: []openapi.Param{idempotencyKey, {
+				Name: middleware.HeaderDryRun, Type: "boolean", Description: "Same as dry_run.",
+			}},
+			Query: []openapi.Param{
+				{Name: "dry_run", Type: "boolean", Description: "Preview the transfer instead of applying it."},
+			},
+			Request:   types.TransferRequest{},
+			Responses: []openapi.Response{{Status: http.StatusOK, Body: types.TransferResponse{}}},
+		},
+		{
+			Method: http.MethodPost, Path: "/transfers/batch", Tag: "transfers", Auth: true,
+			Summary:     "Apply several transfers atomically",
+			Description: "Either every transfer is applied or none is; a failure reports the outcome of each item in results.",
+			Headers:     []openapi.Param{idempotencyKey},
+			Request:     types.BatchTransferRequest{},
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Body: types.BatchTransferResponse{}},
+				{Status: http.StatusUnprocessableEntity, Description: "An item failed and the batch was rolled back", Body: types.ErrorResponse{}},
+			},
+		},
+		{
+			Method: http.MethodPost, Path: "/scheduled-transfers", Tag: "transfers", Auth: true,
+			Summary:   "Schedule a recurring transfer",
+			Headers:   []openapi.Param{idempotencyKey},
+			Request:   types.ScheduledTransferRequest{},
+			Responses: []openapi.Response{{Status: http.StatusCreated, Body: types.ScheduledTransfer{}}},
+		},
+		{
+			Method: http.MethodPost, Path: "/webhooks", Tag: "webhooks", Auth: true,
+			Summary:     "Subscribe to account events",
+			Description: "The URL must resolve only to public addresses. The secret used to sign deliveries is only returned in this response.",
+			Request:     types.WebhookRequest{},
+			Responses:   []openapi.Response{{Status: http.StatusCreated, Body: types.Webhook{}}},
+		},
+		{
+			Method: http.MethodPost, Path: "/graphql", Tag: "graphql", Auth: true,
+			Summary:     "Run a GraphQL query or mutation",
+			Description: "Offers account and transactions queries and deposit, withdraw and transfer mutations. Field errors are returned with 200 in the errors list, carrying the REST error code in extensions.code.",
+			Headers:     []openapi.Param{idempotencyKey},
+			Request:     types.GraphQLRequest{},
+			Responses:   []openapi.Response{{Status: http.StatusOK, Description: "The GraphQL result, with data and errors"}},
+		},
+		{
+			Method: http.MethodGet, Path: "/audit-log", Tag: "admin", Auth: true,
+			Summary:     "List audited admin actions (admin only)",
+			Description: "from and to are inclusive and default to the last 30 days.",
+			Query: []openapi.Param{
+				{Name: "from", Format: "date", Description: "First day, YYYY-MM-DD."},
+				{Name: "to", Format: "date", Description: "Last day, YYYY-MM-DD."},
+			},
+			Responses: []openapi.Response{{Status: http.StatusOK, Body: types.AuditLog{}}},
+		},
+	}
+}
@@ -0,0 +1,68 @@
+package route_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"BankSystemGoLang/handlers"
+	"BankSystemGoLang/route"
+	"BankSystemGoLang/types"
+)
+
+func TestOpenAPIDocumentsEveryRoute(t *testing.T) {
+	spec, err := route.OpenAPI()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the docs handler is called; the rest just have to be routable.
+	pass := func(next echo.HandlerFunc) echo.HandlerFunc { return next }
+	e := echo.New()
+	route.Register(e, route.Handlers{Docs: handlers.NewDocsHandler(spec, route.DocsPage())}, route.Middleware{
+		RequireAuth:  pass,
+		RequireAdmin: pass,
+		Idempotent:   pass,
+		RateLimit:    pass,
+		Audit:        func(types.AuditAction) echo.MiddlewareFunc { return pass },
+	})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, route.SpecPath, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET %s = %d", route.SpecPath, rec.Code)
+	}
+	var doc struct {
+		OpenAPI string                                `json:"openapi"`
+		Paths   map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("spec is not JSON: %v", err)
+	}
+	if !strings.HasPrefix(doc.OpenAPI, "3.") {
+		t.Errorf("openapi = %q, want a 3.x document", doc.OpenAPI)
+	}
+
+	for _, want := range []string{"/accounts", "/accounts/{id}", "/accounts/{id}/deposit", "/transfers", "/transfers/batch", "/graphql"} {
+		if _, ok := doc.Paths[want]; !ok {
+			t.Errorf("spec has no %s", want)
+		}
+	}
+
+	// Every registered route but the docs themselves is documented.
+	param := regexp.MustCompile(`:(\w+)`)
+	for _, r := range e.Routes() {
+		if r.Path == route.SpecPath || r.Path == route.DocsPath {
+			continue
+		}
+		path := param.ReplaceAllString(r.Path, "{$1}")
+		if _, ok := doc.Paths[path][strings.ToLower(r.Method)]; !ok {
+			t.Errorf("%s %s is not in the spec", r.Method, path)
+		}
+	}
+}
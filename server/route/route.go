@@ -0,0 +1,86 @@
+package route
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"BankSystemGoLang/handlers"
+	"BankSystemGoLang/types"
+)
+
+// Handlers groups every HTTP handler the router needs.
+type Handlers struct {
+	Health    *handlers.HealthHandler
+	Metrics   http.Handler
+	Auth      *handlers.AuthHandler
+	Accounts  *handlers.AccountHandler
+	Holds     *handlers.HoldHandler
+	Transfers *handlers.TransferHandler
+	Schedules *handlers.ScheduleHandler
+	Webhooks  *handlers.WebhookHandler
+	Audit     *handlers.AuditHandler
+	GraphQL   *handlers.GraphQLHandler
+	Docs      *handlers.DocsHandler
+}
+
+// Middleware groups the route-level middleware the router needs.
+type Middleware struct {
+	RequireAuth  echo.MiddlewareFunc
+	RequireAdmin echo.MiddlewareFunc
+	Idempotent   echo.MiddlewareFunc
+	RateLimit    echo.MiddlewareFunc
+	// Audit returns the middleware recording an admin's request as action.
+	Audit func(action types.AuditAction) echo.MiddlewareFunc
+}
+
+// Register wires every API endpoint onto the Echo instance. Everything
+// except /health, /metrics, /register, /login, /verify and the API docs goes
+// through RequireAuth, money movements additionally honour
+// Idempotency-Key, and back-office actions require the admin role. Admin
+// actions changing an account are audited. All but the probes and the docs
+// are rate limited.
+func Register(e *echo.Echo, h Handlers, m Middleware) {
+	// authed returns the middleware chain of an authenticated route. The
+	// rate limit runs after RequireAuth so it is keyed on the user.
+	authed := func(extra ...echo.MiddlewareFunc) []echo.MiddlewareFunc {
+		return append([]echo.MiddlewareFunc{m.RequireAuth, m.RateLimit}, extra...)
+	}
+
+	e.GET("/health", h.Health.Check)
+	e.GET("/metrics", echo.WrapHandler(h.Metrics))
+	e.POST("/register", h.Auth.Register, m.RateLimit)
+	e.POST("/login", h.Auth.Login, m.RateLimit)
+	e.GET("/verify", h.Auth.Verify, m.RateLimit)
+	e.POST("/verify/resend", h.Auth.ResendVerification, m.RateLimit)
+	e.GET(SpecPath, h.Docs.Spec)
+	e.GET(DocsPath, h.Docs.UI)
+
+	// Middleware is attached per route: a Group with middleware would add a
+	// catch-all route and turn unknown paths into 401s instead of 404s.
+	e.POST("/accounts", h.Accounts.Create, authed()...)
+	e.GET("/accounts", h.Accounts.List, authed()...)
+	e.GET("/accounts/:id", h.Accounts.Get, authed()...)
+	e.PATCH("/accounts/:id", h.Accounts.Update, authed()...)
+	e.DELETE("/accounts/:id", h.Accounts.Close, authed(m.Audit(types.AuditCloseAccount))...)
+	e.POST("/accounts/:id/deposit", h.Accounts.Deposit, authed(m.Idempotent)...)
+	e.POST("/accounts/:id/withdraw", h.Accounts.Withdraw, authed(m.Idempotent)...)
+	e.GET("/accounts/:id/transactions", h.Accounts.Transactions, authed()...)
+	e.GET("/accounts/:id/statement", h.Accounts.Statement, authed()...)
+	e.POST("/accounts/:id/accrue-interest", h.Accounts.AccrueInterest,
+		authed(m.RequireAdmin, m.Audit(types.AuditAccrueInterest))...)
+	e.POST("/accounts/:id/freeze", h.Accounts.Freeze, authed(m.RequireAdmin, m.Audit(types.AuditFreeze))...)
+	e.POST("/accounts/:id/unfreeze", h.Accounts.Unfreeze, authed(m.RequireAdmin, m.Audit(types.AuditUnfreeze))...)
+	e.POST("/accounts/:id/holds", h.Holds.Create, authed(m.Idempotent)...)
+	e.POST("/holds/:id/capture", h.Holds.Capture, authed(m.Idempotent)...)
+	e.POST("/holds/:id/release", h.Holds.Release, authed()...)
+
+	e.POST("/transfers", h.Transfers.Create, authed(m.Idempotent)...)
+	e.POST("/transfers/batch", h.Transfers.Batch, authed(m.Idempotent)...)
+	e.POST("/scheduled-transfers", h.Schedules.Create, authed(m.Idempotent)...)
+	e.POST("/webhooks", h.Webhooks.Create, authed()...)
+	e.POST("/graphql", h.GraphQL.Serve, authed(m.Idempotent)...)
+
+	e.GET("/admin/accounts", h.Accounts.ListAll, authed(m.RequireAdmin)...)
+	e.GET("/audit-log", h.Audit.List, authed(m.RequireAdmin)...)
+}
@@ -0,0 +1,63 @@
+// Package scheduler runs background jobs on a fixed interval for as long as
+// the server is up.
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"BankSystemGoLang/services"
+)
+
+// Scheduler executes due scheduled transfers every interval.
+type Scheduler struct {
+	schedules *services.ScheduleService
+	interval  time.Duration
+	log       *zap.Logger
+}
+
+func New(schedules *services.ScheduleService, interval time.Duration, log *zap.Logger) *Scheduler {
+	return &Scheduler{schedules: schedules, interval: interval, log: log}
+}
+
+// Run ticks until ctx is cancelled. A run already in progress is allowed
+// to finish, so no transfer is interrupted halfway through.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	s.log.Info("scheduler started", zap.Duration("interval", s.interval))
+	for {
+		select {
+		case <-ctx.Done():
+			s.log.Info("scheduler stopped")
+			return
+		case <-ticker.C:
+			s.RunOnce(ctx)
+		}
+	}
+}
+
+// RunOnce executes the transfers that are currently due and logs each
+// outcome. Failed runs are retried on the next tick.
+func (s *Scheduler) RunOnce(ctx context.Context) {
+	runs, err := s.schedules.RunDue(ctx)
+	for _, run := range runs {
+		fields := []zap.Field{
+			zap.Int64("schedule_id", run.Schedule.ID),
+			zap.Int64("from_id", run.Schedule.FromID),
+			zap.Int64("to_id", run.Schedule.ToID),
+			zap.Stringer("amount", run.Schedule.Amount),
+		}
+		if run.Err != nil {
+			s.log.Warn("scheduled transfer failed, will retry", append(fields, zap.Error(run.Err))...)
+			continue
+		}
+		s.log.Info("scheduled transfer executed", append(fields, zap.Time("next_run_at", run.Schedule.NextRunAt))...)
+	}
+	if err != nil {
+		s.log.Error("run scheduled transfers", zap.Error(err))
+	}
+}
@@ -0,0 +1,134 @@
+// Package seed fills a store with demo users, accounts and transactions
+// for local development and demos.
+package seed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"BankSystemGoLang/clock"
+	"BankSystemGoLang/services"
+	"BankSystemGoLang/store"
+	"BankSystemGoLang/types"
+)
+
+// DemoPassword is the password of every demo user.
+const DemoPassword = "demo-password"
+
+type demoUser struct {
+	email    string
+	accounts []types.CreateAccountRequest
+}
+
+var demoUsers = []demoUser{
+	{
+		email: "alice@example.com",
+		accounts: []types.CreateAccountRequest{
+			{OwnerName: "Alice Demo", Email: "alice@example.com", InitialBalance: 125000, AccountType: types.AccountChecking, OverdraftLimit: 20000},
+			{OwnerName: "Alice Demo", Email: "alice+savings@example.com", InitialBalance: 500000, AccountType: types.AccountSavings},
+		},
+	},
+	{
+		email: "bob@example.com",
+		accounts: []types.CreateAccountRequest{
+			{OwnerName: "Bob Demo", Email: "bob@example.com", InitialBalance: 30000, AccountType: types.AccountChecking},
+		},
+	},
+}
+
+// demoActivity is posted after the accounts are opened, in order. Each
+// item names accounts by email; to is only set on transfers.
+var demoActivity = []struct {
+	kind     types.TransactionType
+	from, to string
+	amount   types.Money
+}{
+	{kind: types.TransactionDeposit, from: "alice@example.com", amount: 40000},
+	{kind: types.TransactionWithdrawal, from: "alice@example.com", amount: 7550},
+	{kind: types.TransactionTransferOut, from: "alice@example.com", to: "alice+savings@example.com", amount: 25000},
+	{kind: types.TransactionTransferOut, from: "alice@example.com", to: "bob@example.com", amount: 12000},
+	{kind: types.TransactionDeposit, from: "bob@example.com", amount: 5000},
+}
+
+// DemoEmails are the addresses the demo users log in with.
+func DemoEmails() []string {
+	emails := make([]string, len(demoUsers))
+	for i, u := range demoUsers {
+		emails[i] = u.email
+	}
+	return emails
+}
+
+// Demo creates the demo users, already verified, and opens their accounts
+// with a few deposits, withdrawals and transfers between them. It is
+// idempotent: users that already exist are reused, and each account is
+// keyed on its email, so a run that stopped part way is completed by the
+// next and a full second run creates nothing. It returns how many
+// accounts it opened.
+func Demo(ctx context.Context, s store.Store, auth *services.AuthService, accounts *services.AccountService, clk clock.Clock) (int, error) {
+	byEmail := map[string]types.Account{}
+	opened := map[string]bool{}
+	for _, u := range demoUsers {
+		user, err := demoLogin(ctx, s, auth, u.email, clk)
+		if err != nil {
+			return len(opened), err
+		}
+		existing, err := accounts.List(ctx, user.ID, "", services.MaxAccountLimit, 0)
+		if err != nil {
+			return len(opened), fmt.Errorf("list accounts of %s: %w", u.email, err)
+		}
+		for _, account := range existing.Accounts {
+			byEmail[account.Email] = account
+		}
+		for _, req := range u.accounts {
+			if _, ok := byEmail[req.Email]; ok {
+				continue
+			}
+			account, err := accounts.Create(ctx, user.ID, req)
+			if err != nil {
+				return len(opened), fmt.Errorf("open account %s: %w", req.Email, err)
+			}
+			byEmail[req.Email] = account
+			opened[req.Email] = true
+		}
+	}
+
+	// Activity is only posted from accounts opened now, so a second run
+	// does not post it again.
+	for _, a := range demoActivity {
+		if !opened[a.from] {
+			continue
+		}
+		from, to := byEmail[a.from].ID, byEmail[a.to].ID
+		var err error
+		switch a.kind {
+		case types.TransactionDeposit:
+			_, err = accounts.Deposit(ctx, from, a.amount, 0)
+		case types.TransactionWithdrawal:
+			_, err = accounts.Withdraw(ctx, from, a.amount, 0)
+		case types.TransactionTransferOut:
+			_, _, _, err = accounts.Transfer(ctx, from, to, a.amount)
+		}
+		if err != nil {
+			return len(opened), fmt.Errorf("seed %s from %s: %w", a.kind, a.from, err)
+		}
+	}
+	return len(opened), nil
+}
+
+// demoLogin returns the demo user with the given email, registering it
+// first if needed, and marks it verified so it can log in straight away.
+func demoLogin(ctx context.Context, s store.Store, auth *services.AuthService, email string, clk clock.Clock) (types.User, error) {
+	user, err := s.GetUserByEmail(ctx, email)
+	if errors.Is(err, store.ErrNotFound) {
+		user, err = auth.Register(ctx, types.RegisterRequest{Email: email, Password: DemoPassword})
+	}
+	if err != nil {
+		return types.User{}, fmt.Errorf("register %s: %w", email, err)
+	}
+	if err := s.MarkUserVerified(ctx, user.ID, clk.Now()); err != nil {
+		return types.User{}, fmt.Errorf("verify %s: %w", email, err)
+	}
+	return user, nil
+}
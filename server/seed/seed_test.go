@@ -0,0 +1,101 @@
+package seed_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"BankSystemGoLang/clock"
+	"BankSystemGoLang/seed"
+	"BankSystemGoLang/services"
+	"BankSystemGoLang/store"
+	"BankSystemGoLang/types"
+)
+
+var ctx = context.Background()
+
+type demo struct {
+	store    store.Store
+	auth     *services.AuthService
+	accounts *services.AccountService
+	clock    clock.Clock
+}
+
+func newDemo() demo {
+	st := store.NewMemoryStore()
+	clk := clock.NewMock(time.Date(2025, time.March, 10, 12, 0, 0, 0, time.UTC))
+	return demo{
+		store:    st,
+		auth:     services.NewAuthService(st, "seed-test-secret", nil, clk),
+		accounts: services.NewAccountService(st, "seed-test-secret", big.NewRat(0, 1), services.StaticRates{}, clk),
+		clock:    clk,
+	}
+}
+
+func (d demo) run(t *testing.T) int {
+	t.Helper()
+	opened, err := seed.Demo(ctx, d.store, d.auth, d.accounts, d.clock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return opened
+}
+
+func (d demo) count(t *testing.T) int {
+	t.Helper()
+	n, err := d.accounts.Count(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestDemoTwice(t *testing.T) {
+	d := newDemo()
+	if opened := d.run(t); opened != 3 {
+		t.Fatalf("first run opened %d accounts, want 3", opened)
+	}
+	if opened := d.run(t); opened != 0 {
+		t.Errorf("second run opened %d accounts, want 0", opened)
+	}
+	if n := d.count(t); n != 3 {
+		t.Errorf("%d accounts, want 3", n)
+	}
+
+	// The activity was posted once.
+	want := map[int64]types.Money{1: 1204_50, 2: 5250_00, 3: 470_00}
+	for id, balance := range want {
+		account, err := d.accounts.Get(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if account.Balance != balance {
+			t.Errorf("%s balance = %s, want %s", account.Email, account.Balance, balance)
+		}
+	}
+}
+
+func TestDemoCompletesAPartialRun(t *testing.T) {
+	d := newDemo()
+	// A run that stopped after opening Alice's first account.
+	alice, err := d.auth.Register(ctx, types.RegisterRequest{Email: "alice@example.com", Password: seed.DemoPassword})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.accounts.Create(ctx, alice.ID, types.CreateAccountRequest{OwnerName: "Alice Demo", Email: "alice@example.com"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if opened := d.run(t); opened != 2 {
+		t.Errorf("opened %d accounts, want the 2 missing ones", opened)
+	}
+	if n := d.count(t); n != 3 {
+		t.Errorf("%d accounts, want 3", n)
+	}
+	for _, email := range seed.DemoEmails() {
+		if _, err := d.auth.Login(ctx, types.LoginRequest{Email: email, Password: seed.DemoPassword}); err != nil {
+			t.Errorf("login %s: %v", email, err)
+		}
+	}
+}
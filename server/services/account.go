@@ -0,0 +1,826 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/mail"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"BankSystemGoLang/clock"
+	"BankSystemGoLang/store"
+	"BankSystemGoLang/types"
+)
+
+var (
+	ErrOwnerNameRequired = errors.New("owner_name is required")
+	ErrInvalidEmail      = errors.New("email is not valid")
+	ErrNegativeBalance   = errors.New("initial_balance must not be negative")
+	ErrDuplicateEmail    = errors.New("an account with this email already exists")
+	ErrAccountNotFound   = errors.New("account not found")
+	ErrInvalidAmount     = errors.New("amount must be greater than zero")
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	ErrSameAccount       = errors.New("cannot transfer to the same account")
+	ErrInvalidPagination = errors.New("limit must be positive and offset must not be negative")
+	ErrCursorWithOffset  = errors.New("cursor and offset cannot be combined")
+	ErrAccountClosed     = errors.New("account is closed")
+	ErrAccountFrozen     = errors.New("account is frozen")
+	ErrInvalidStatus     = errors.New("status must be open or closed")
+	ErrBalanceNotZero    = errors.New("account balance must be zero before closing; withdraw the remaining funds first")
+	ErrNotSavings        = errors.New("interest only accrues on savings accounts")
+	ErrInvalidDateRange  = errors.New("from must not be after to")
+	ErrInvalidOverdraft  = errors.New("overdraft_limit must not be negative and is only available on checking accounts")
+	ErrActiveHolds       = errors.New("account has active holds; capture or release them before closing")
+	ErrVersionMismatch   = errors.New("account has changed since the version given in If-Match")
+	ErrConcurrentUpdate  = errors.New("account is being updated concurrently; try again")
+	ErrInvalidDailyLimit = errors.New("daily_withdrawal_limit must not be negative")
+	ErrDailyLimit        = errors.New("withdrawal exceeds the account's daily limit")
+)
+
+// DailyLimitError is the ErrDailyLimit returned by Withdraw, PlaceHold and
+// CaptureHold. Remaining is how much more the account may withdraw today.
+type DailyLimitError struct {
+	Limit     types.Money
+	Remaining types.Money
+}
+
+func (e *DailyLimitError) Error() string {
+	return fmt.Sprintf("withdrawal exceeds the daily limit of %s; %s remains today", e.Limit, e.Remaining)
+}
+
+func (e *DailyLimitError) Unwrap() error { return ErrDailyLimit }
+
+const (
+	// DefaultTransactionLimit is the page size used when the caller gives none.
+	DefaultTransactionLimit = 50
+
+	// DefaultAccountLimit and MaxAccountLimit bound account list pages.
+	DefaultAccountLimit = 20
+	MaxAccountLimit     = 100
+
+	// DefaultStatementDays is the statement period used when the caller
+	// gives no dates.
+	DefaultStatementDays = 30
+
+	// DefaultCurrency is used for accounts opened without a currency.
+	DefaultCurrency = "USD"
+
+	// DefaultDailyWithdrawalLimit applies to accounts opened without one.
+	DefaultDailyWithdrawalLimit types.Money = 5000_00
+
+	// daysPerYear pro-rates the annual interest rate.
+	daysPerYear = 365
+
+	// maxConflictRetries bounds the attempts at an update that keeps losing
+	// the optimistic version check to other writers.
+	maxConflictRetries = 3
+)
+
+// AccountService holds the business logic for bank accounts. Balance
+// changes are serialised per account so unrelated accounts never contend.
+// Every time it records or compares against is read from its clock.
+type AccountService struct {
+	store        store.Store
+	interestRate *big.Rat // annual, as a fraction: 0.02 is 2%
+	rates        RateProvider
+	locks        sync.Map // account ID -> *sync.Mutex
+	subscribers  []func(types.Event)
+	clock        clock.Clock
+	secret       []byte // signs transaction cursors
+}
+
+func NewAccountService(s store.Store, secret string, interestRate *big.Rat, rates RateProvider, clk clock.Clock) *AccountService {
+	return &AccountService{store: s, secret: []byte(secret), interestRate: interestRate, rates: rates, clock: clk}
+}
+
+// Subscribe registers fn to be called after every committed deposit,
+// withdrawal and transfer. fn runs on the request goroutine and must not
+// block. Subscribe is not safe to call once the service is in use.
+func (s *AccountService) Subscribe(fn func(types.Event)) {
+	s.subscribers = append(s.subscribers, fn)
+}
+
+// Create validates the request and stores a new account owned by userID.
+func (s *AccountService) Create(ctx context.Context, userID int64, req types.CreateAccountRequest) (types.Account, error) {
+	name := strings.TrimSpace(req.OwnerName)
+	if name == "" {
+		return types.Account{}, ErrOwnerNameRequired
+	}
+	if !validEmail(req.Email) {
+		return types.Account{}, ErrInvalidEmail
+	}
+	if req.InitialBalance < 0 {
+		return types.Account{}, ErrNegativeBalance
+	}
+
+	accountType := req.AccountType
+	if accountType == "" {
+		accountType = types.AccountChecking
+	}
+	if req.OverdraftLimit < 0 || (req.OverdraftLimit > 0 && accountType != types.AccountChecking) {
+		return types.Account{}, ErrInvalidOverdraft
+	}
+	currency := req.Currency
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+	if req.DailyWithdrawalLimit < 0 {
+		return types.Account{}, ErrInvalidDailyLimit
+	}
+	dailyLimit := req.DailyWithdrawalLimit
+	if dailyLimit == 0 {
+		dailyLimit = DefaultDailyWithdrawalLimit
+	}
+
+	account := types.Account{
+		UserID:               userID,
+		OwnerName:            name,
+		Email:                req.Email,
+		AccountType:          accountType,
+		Currency:             currency,
+		Balance:              req.InitialBalance,
+		OverdraftLimit:       req.OverdraftLimit,
+		DailyWithdrawalLimit: dailyLimit,
+		Status:               types.AccountOpen,
+		CreatedAt:            s.clock.Now().UTC(),
+	}
+	err := s.store.CreateAccount(ctx, &account)
+	if errors.Is(err, store.ErrDuplicateEmail) {
+		return types.Account{}, ErrDuplicateEmail
+	}
+	if err != nil {
+		return types.Account{}, err
+	}
+
+	return account, nil
+}
+
+// Get returns the account with the given ID.
+func (s *AccountService) Get(ctx context.Context, id int64) (types.Account, error) {
+	return loadAccount(ctx, s.store, id)
+}
+
+// loadAccount reads an account through tx, which may be the store itself.
+func loadAccount(ctx context.Context, tx store.Tx, id int64) (types.Account, error) {
+	account, err := tx.GetAccount(ctx, id)
+	if errors.Is(err, store.ErrNotFound) {
+		return types.Account{}, ErrAccountNotFound
+	}
+	return account, err
+}
+
+// List returns a page of the user's accounts, or of every user's when
+// userID is zero, optionally filtered by status. Out-of-range limits and
+// offsets are clamped rather than rejected.
+func (s *AccountService) List(ctx context.Context, userID int64, status types.AccountStatus, limit, offset int) (types.AccountPage, error) {
+	if status != "" && status != types.AccountOpen && status != types.AccountClosed {
+		return types.AccountPage{}, ErrInvalidStatus
+	}
+	limit = min(max(limit, 1), MaxAccountLimit)
+	offset = max(offset, 0)
+
+	accounts, total, err := s.store.ListAccounts(ctx, store.AccountFilter{
+		UserID: userID,
+		Status: status,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return types.AccountPage{}, err
+	}
+	return types.AccountPage{Accounts: accounts, Total: total, Limit: limit, Offset: offset}, nil
+}
+
+// Deposit adds amount to the account balance and returns the updated
+// account. A non-zero version must match the account's current version, as
+// for an If-Match precondition, or ErrVersionMismatch is returned.
+func (s *AccountService) Deposit(ctx context.Context, id int64, amount types.Money, version int64) (types.Account, error) {
+	if amount <= 0 {
+		return types.Account{}, ErrInvalidAmount
+	}
+	if _, err := s.Get(ctx, id); err != nil {
+		return types.Account{}, err
+	}
+
+	unlock := s.lock(id)
+	defer unlock()
+
+	var account types.Account
+	err := retryOnConflict(func() error {
+		return s.store.Atomic(ctx, func(tx store.Tx) (err error) {
+			account, err = loadAccount(ctx, tx, id)
+			if err != nil {
+				return err
+			}
+			if err := checkVersion(account, version); err != nil {
+				return err
+			}
+			if err := movable(account); err != nil {
+				return err
+			}
+			account.Balance += amount
+			if err := tx.UpdateBalance(ctx, s.ledgerUpdate(account, types.TransactionDeposit, amount)); err != nil {
+				return err
+			}
+			account.Version++
+			return nil
+		})
+	})
+	if err != nil {
+		return types.Account{}, err
+	}
+	s.publish(types.Event{Type: types.EventDeposit, AccountID: id, Amount: amount})
+	return account, nil
+}
+
+// Withdraw deducts amount from the account balance. When the balance and
+// overdraft limit together do not cover the amount it returns
+// ErrInsufficientFunds together with the untouched account so callers can
+// report the current balance. Withdrawals taking the day's total (UTC),
+// active holds included, past the account's daily limit fail with a
+// *DailyLimitError. The checks
+// and the update run in one store transaction. version is checked as for
+// Deposit.
+func (s *AccountService) Withdraw(ctx context.Context, id int64, amount types.Money, version int64) (types.Account, error) {
+	if amount <= 0 {
+		return types.Account{}, ErrInvalidAmount
+	}
+	if _, err := s.Get(ctx, id); err != nil {
+		return types.Account{}, err
+	}
+
+	unlock := s.lock(id)
+	defer unlock()
+
+	var account types.Account
+	err := retryOnConflict(func() error {
+		return s.store.Atomic(ctx, func(tx store.Tx) (err error) {
+			account, err = loadAccount(ctx, tx, id)
+			if err != nil {
+				return err
+			}
+			if err := checkVersion(account, version); err != nil {
+				return err
+			}
+			if err := movable(account); err != nil {
+				return err
+			}
+			if !covers(account, amount) {
+				return ErrInsufficientFunds
+			}
+			now := s.clock.Now().UTC()
+			remaining, err := dailyRemaining(ctx, tx, account, now)
+			if err != nil {
+				return err
+			}
+			if amount > remaining {
+				return &DailyLimitError{Limit: account.DailyWithdrawalLimit, Remaining: remaining}
+			}
+
+			account.Balance -= amount
+			update := s.ledgerUpdate(account, types.TransactionWithdrawal, amount)
+			update.Entry.CreatedAt = now // on the day the limit was counted for
+			if err := tx.UpdateBalance(ctx, update); err != nil {
+				return err
+			}
+			account.Version++
+			return nil
+		})
+	})
+	if errors.Is(err, ErrInsufficientFunds) {
+		return account, err
+	}
+	if err != nil {
+		return types.Account{}, err
+	}
+	s.publish(types.Event{Type: types.EventWithdrawal, AccountID: id, Amount: amount})
+	return account, nil
+}
+
+// Conversion is the currency conversion applied to a transfer.
+type Conversion struct {
+	Rate     string // decimal, e.g. "1.08"
+	Credited types.Money
+}
+
+// Transfer moves amount from one account to another in a single store
+// transaction, so on SQLite the balances it checks cannot change before
+// it writes. Both account locks are always taken lowest ID first so two transfers over
+// the same pair in opposite directions cannot deadlock. On
+// ErrInsufficientFunds the untouched source account is returned.
+//
+// Between accounts of different currencies amount is debited in the source
+// currency and credited converted at the rate provider's current rate. The
+// rate is fetched before anything is locked or written, so when the
+// provider fails the transfer fails with ErrExchangeRate and changes
+// nothing. The applied conversion is returned, or nil when both accounts
+// share a currency.
+func (s *AccountService) Transfer(ctx context.Context, fromID, toID int64, amount types.Money) (from, to types.Account, fx *Conversion, err error) {
+	fail := func(err error) (types.Account, types.Account, *Conversion, error) {
+		return types.Account{}, types.Account{}, nil, err
+	}
+	from, to, fx, err = s.prepareTransfer(ctx, fromID, toID, amount)
+	if err != nil {
+		return fail(err)
+	}
+
+	unlock := s.lock(fromID, toID)
+	defer unlock()
+
+	err = retryOnConflict(func() error {
+		return s.store.Atomic(ctx, func(tx store.Tx) (err error) {
+			from, err = loadAccount(ctx, tx, fromID)
+			if err != nil {
+				return err
+			}
+			to, err = loadAccount(ctx, tx, toID)
+			if err != nil {
+				return err
+			}
+			out, in, err := s.move(&from, &to, amount, fx)
+			if err != nil {
+				return err
+			}
+			return tx.UpdateBalance(ctx, out, in)
+		})
+	})
+	if errors.Is(err, ErrInsufficientFunds) {
+		return from, to, nil, err
+	}
+	if err != nil {
+		return fail(err)
+	}
+	s.publish(types.Event{Type: types.EventTransfer, AccountID: fromID, ToAccountID: toID, Amount: amount})
+	return from, to, fx, nil
+}
+
+// PreviewTransfer checks a transfer as Transfer would and returns the
+// accounts with the balances it would leave and the conversion it would
+// apply, without writing anything. The rate is the provider's current one,
+// so a later Transfer may convert at another. On ErrInsufficientFunds the
+// untouched source account is returned.
+func (s *AccountService) PreviewTransfer(ctx context.Context, fromID, toID int64, amount types.Money) (from, to types.Account, fx *Conversion, err error) {
+	from, to, fx, err = s.prepareTransfer(ctx, fromID, toID, amount)
+	if err != nil {
+		return types.Account{}, types.Account{}, nil, err
+	}
+	if _, _, err := s.move(&from, &to, amount, fx); err != nil {
+		if errors.Is(err, ErrInsufficientFunds) {
+			return from, to, nil, err
+		}
+		return types.Account{}, types.Account{}, nil, err
+	}
+	return from, to, fx, nil
+}
+
+// prepareTransfer validates a transfer's arguments, loads both accounts and
+// quotes the conversion between them.
+func (s *AccountService) prepareTransfer(ctx context.Context, fromID, toID int64, amount types.Money) (from, to types.Account, fx *Conversion, err error) {
+	if fromID == toID {
+		return from, to, nil, ErrSameAccount
+	}
+	if amount <= 0 {
+		return from, to, nil, ErrInvalidAmount
+	}
+	if from, err = s.Get(ctx, fromID); err != nil {
+		return from, to, nil, err
+	}
+	if to, err = s.Get(ctx, toID); err != nil {
+		return from, to, nil, err
+	}
+	fx, err = s.quote(from, to, amount)
+	return from, to, fx, err
+}
+
+// quote returns the conversion a transfer of amount between the two
+// accounts needs, or nil when they share a currency.
+func (s *AccountService) quote(from, to types.Account, amount types.Money) (*Conversion, error) {
+	if from.Currency == to.Currency {
+		return nil, nil
+	}
+	rate, err := s.rates.Rate(from.Currency, to.Currency)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrExchangeRate, err)
+	}
+	credited := convert(amount, rate)
+	if credited <= 0 {
+		return nil, ErrInvalidAmount
+	}
+	return &Conversion{Rate: formatRate(rate), Credited: credited}, nil
+}
+
+// move applies a transfer to the loaded from and to accounts and returns
+// the two store updates recording it. The accounts are left untouched when
+// it fails. Both versions advance, so moves over the same accounts can be
+// chained into one UpdateBalance call.
+func (s *AccountService) move(from, to *types.Account, amount types.Money, fx *Conversion) (out, in store.BalanceUpdate, err error) {
+	if err := movable(*from); err != nil {
+		return out, in, err
+	}
+	if err := movable(*to); err != nil {
+		return out, in, err
+	}
+	if !covers(*from, amount) {
+		return out, in, ErrInsufficientFunds
+	}
+
+	credited := amount
+	if fx != nil {
+		credited = fx.Credited
+	}
+	from.Balance -= amount
+	to.Balance += credited
+	out = s.ledgerUpdate(*from, types.TransactionTransferOut, amount)
+	in = s.ledgerUpdate(*to, types.TransactionTransferIn, credited)
+	if fx != nil {
+		out.Entry.ExchangeRate, in.Entry.ExchangeRate = fx.Rate, fx.Rate
+		out.Entry.ConvertedAmount = &credited
+	}
+	from.Version++
+	to.Version++
+	return out, in, nil
+}
+
+// UpdateContact changes the account's owner name and email, leaving the
+// one the request omits as it was. version is checked as for Deposit.
+func (s *AccountService) UpdateContact(ctx context.Context, id int64, req types.UpdateAccountRequest, version int64) (types.Account, error) {
+	if req.OwnerName != nil && strings.TrimSpace(*req.OwnerName) == "" {
+		return types.Account{}, ErrOwnerNameRequired
+	}
+	if req.Email != nil && !validEmail(*req.Email) {
+		return types.Account{}, ErrInvalidEmail
+	}
+	if _, err := s.Get(ctx, id); err != nil {
+		return types.Account{}, err
+	}
+
+	unlock := s.lock(id)
+	defer unlock()
+
+	var account types.Account
+	err := retryOnConflict(func() (err error) {
+		account, err = s.Get(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := checkVersion(account, version); err != nil {
+			return err
+		}
+		if req.OwnerName == nil && req.Email == nil {
+			return nil
+		}
+
+		if req.OwnerName != nil {
+			account.OwnerName = strings.TrimSpace(*req.OwnerName)
+		}
+		if req.Email != nil {
+			account.Email = *req.Email
+		}
+		err = s.store.UpdateContact(ctx, id, account.Version, account.OwnerName, account.Email)
+		if errors.Is(err, store.ErrDuplicateEmail) {
+			return ErrDuplicateEmail
+		}
+		if err != nil {
+			return err
+		}
+		account.Version++
+		return nil
+	})
+	if err != nil {
+		return types.Account{}, err
+	}
+	return account, nil
+}
+
+// Close soft-deletes an account. Only accounts with a zero balance and no
+// active holds can be closed; the account and its ledger remain readable
+// afterwards. version is checked as for Deposit.
+func (s *AccountService) Close(ctx context.Context, id int64, version int64) (types.Account, error) {
+	if _, err := s.Get(ctx, id); err != nil {
+		return types.Account{}, err
+	}
+
+	unlock := s.lock(id)
+	defer unlock()
+
+	var account types.Account
+	err := retryOnConflict(func() (err error) {
+		account, err = s.Get(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := checkVersion(account, version); err != nil {
+			return err
+		}
+		if account.Status == types.AccountClosed {
+			return ErrAccountClosed
+		}
+		if account.Balance != 0 {
+			return ErrBalanceNotZero
+		}
+		if account.Held != 0 {
+			return ErrActiveHolds
+		}
+
+		closedAt := s.clock.Now().UTC()
+		if err := s.store.CloseAccount(ctx, id, account.Version, closedAt); err != nil {
+			return err
+		}
+		account.Status = types.AccountClosed
+		account.ClosedAt = &closedAt
+		account.Version++
+		return nil
+	})
+	if err != nil {
+		return types.Account{}, err
+	}
+	return account, nil
+}
+
+// AccrueInterest posts the interest a savings account has earned since its
+// last accrual (or since it was opened) at the configured annual rate,
+// pro-rated per whole calendar day. Accruing again on the same UTC day
+// posts nothing.
+func (s *AccountService) AccrueInterest(ctx context.Context, id int64) (types.InterestResponse, error) {
+	if _, err := s.Get(ctx, id); err != nil {
+		return types.InterestResponse{}, err
+	}
+
+	unlock := s.lock(id)
+	defer unlock()
+
+	var result types.InterestResponse
+	err := retryOnConflict(func() error {
+		account, err := s.Get(ctx, id)
+		if err != nil {
+			return err
+		}
+		if account.AccountType != types.AccountSavings {
+			return ErrNotSavings
+		}
+		if err := movable(account); err != nil {
+			return err
+		}
+
+		now := s.clock.Now().UTC()
+		since := account.CreatedAt
+		if account.LastAccruedAt != nil {
+			since = *account.LastAccruedAt
+		}
+		days := daysBetween(since, now)
+		result = types.InterestResponse{AccountID: id, Days: days, Balance: account.Balance}
+		if days == 0 {
+			return nil
+		}
+
+		// Interest on a positive balance that rounds to zero posts nothing
+		// and leaves the accrual date alone, so those days are counted again
+		// at the next accrual instead of being lost. A balance of zero or
+		// less earns nothing for the days, so they are used up.
+		result.Interest = s.interest(account.Balance, days)
+		update := balanceUpdate(account)
+		switch {
+		case result.Interest > 0:
+			account.Balance += result.Interest
+			update = s.ledgerUpdate(account, types.TransactionInterest, result.Interest)
+		case account.Balance > 0:
+			return nil
+		}
+		update.AccruedAt = &now
+		if err := s.store.UpdateBalance(ctx, update); err != nil {
+			return err
+		}
+		result.Balance = account.Balance
+		return nil
+	})
+	if err != nil {
+		return types.InterestResponse{}, err
+	}
+	return result, nil
+}
+
+// convert returns amount * rate rounded half-up to the cent.
+func convert(amount types.Money, rate *big.Rat) types.Money {
+	r := new(big.Rat).SetInt64(int64(amount))
+	return roundCents(r.Mul(r, rate))
+}
+
+// formatRate renders rate as a decimal with at most eight places.
+func formatRate(rate *big.Rat) string {
+	s := strings.TrimRight(rate.FloatString(8), "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+// interest returns balance * rate * days / 365 rounded half-up to the cent.
+// Negative balances earn nothing.
+func (s *AccountService) interest(balance types.Money, days int) types.Money {
+	if balance <= 0 || s.interestRate == nil {
+		return 0
+	}
+	r := new(big.Rat).SetInt64(int64(balance))
+	r.Mul(r, s.interestRate)
+	r.Mul(r, big.NewRat(int64(days), daysPerYear))
+	return roundCents(r)
+}
+
+// roundCents rounds a non-negative number of cents half-up: adding one
+// half and truncating.
+func roundCents(r *big.Rat) types.Money {
+	r = new(big.Rat).Add(r, big.NewRat(1, 2))
+	return types.Money(new(big.Int).Quo(r.Num(), r.Denom()).Int64())
+}
+
+// daysBetween counts the UTC calendar days from since to now.
+func daysBetween(since, now time.Time) int {
+	return max(int(startOfDay(now).Sub(startOfDay(since)).Hours()/24), 0)
+}
+
+// startOfDay truncates t to midnight UTC.
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// Count returns the number of accounts across all users.
+func (s *AccountService) Count(ctx context.Context) (int, error) {
+	_, total, err := s.store.ListAccounts(ctx, store.AccountFilter{Limit: 1})
+	return total, err
+}
+
+// Transactions returns a page of the account's ledger, newest first. The
+// page starts after cursor when one is given, otherwise offset entries in.
+// NextCursor is set whenever older entries remain.
+func (s *AccountService) Transactions(ctx context.Context, id int64, limit, offset int, cursor string) (types.TransactionPage, error) {
+	if limit <= 0 || offset < 0 {
+		return types.TransactionPage{}, ErrInvalidPagination
+	}
+	filter := store.TransactionFilter{AccountID: id, Limit: limit + 1, Offset: offset}
+	if cursor != "" {
+		if offset != 0 {
+			return types.TransactionPage{}, ErrCursorWithOffset
+		}
+		before, err := s.decodeCursor(id, cursor)
+		if err != nil {
+			return types.TransactionPage{}, err
+		}
+		filter.BeforeID = before
+	}
+	if _, err := s.Get(ctx, id); err != nil {
+		return types.TransactionPage{}, err
+	}
+
+	// One extra entry is fetched to tell whether another page follows.
+	transactions, err := s.store.ListTransactions(ctx, filter)
+	if err != nil {
+		return types.TransactionPage{}, err
+	}
+	page := types.TransactionPage{Transactions: transactions, Limit: limit, Offset: offset}
+	if len(transactions) > limit {
+		page.Transactions = transactions[:limit]
+		page.NextCursor = s.encodeCursor(id, page.Transactions[limit-1])
+	}
+	return page, nil
+}
+
+func (s *AccountService) publish(event types.Event) {
+	event.OccurredAt = s.clock.Now().UTC()
+	for _, fn := range s.subscribers {
+		fn(event)
+	}
+}
+
+// Statement returns the account's ledger entries from the start of the
+// from day to the end of the to day.
+func (s *AccountService) Statement(ctx context.Context, id int64, from, to time.Time) (types.Statement, error) {
+	from, to = startOfDay(from), startOfDay(to)
+	if from.After(to) {
+		return types.Statement{}, ErrInvalidDateRange
+	}
+	account, err := s.Get(ctx, id)
+	if err != nil {
+		return types.Statement{}, err
+	}
+
+	entries, err := s.store.ListTransactionsBetween(ctx, id, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		return types.Statement{}, err
+	}
+	return types.Statement{Account: account, From: from, To: to, Transactions: entries}, nil
+}
+
+// dailyRemaining is how much more the account may withdraw on now's UTC
+// day, going by the withdrawals and captured holds already in its ledger as
+// read through tx. Active holds reserve their amount against the limit
+// until they are captured or released, so capturing one cannot take the
+// day past it.
+func dailyRemaining(ctx context.Context, tx store.Tx, account types.Account, now time.Time) (types.Money, error) {
+	day := startOfDay(now)
+	entries, err := tx.ListTransactionsBetween(ctx, account.ID, day, day.AddDate(0, 0, 1))
+	if err != nil {
+		return 0, err
+	}
+	var withdrawn types.Money
+	for _, entry := range entries {
+		switch entry.Type {
+		case types.TransactionWithdrawal, types.TransactionCapture:
+			withdrawn += entry.Amount
+		}
+	}
+	return max(account.DailyWithdrawalLimit-withdrawn-account.Held, 0), nil
+}
+
+// movable returns why no money may move in or out of account, if anything.
+func movable(account types.Account) error {
+	switch {
+	case account.Status == types.AccountClosed:
+		return ErrAccountClosed
+	case account.Frozen:
+		return ErrAccountFrozen
+	}
+	return nil
+}
+
+// covers reports whether account can pay out amount without its available
+// balance falling below -OverdraftLimit.
+func covers(account types.Account, amount types.Money) bool {
+	return account.Available()-amount >= -account.OverdraftLimit
+}
+
+// balanceUpdate builds the store update persisting account's new balance,
+// held total and frozen flag, conditional on the version it was read at.
+func balanceUpdate(account types.Account) store.BalanceUpdate {
+	return store.BalanceUpdate{
+		AccountID: account.ID,
+		Balance:   account.Balance,
+		Held:      account.Held,
+		Frozen:    account.Frozen,
+		Version:   account.Version,
+	}
+}
+
+// ledgerUpdate is balanceUpdate together with the ledger entry that
+// explains the change.
+func (s *AccountService) ledgerUpdate(account types.Account, kind types.TransactionType, amount types.Money) store.BalanceUpdate {
+	update := balanceUpdate(account)
+	update.Entry = &types.Transaction{
+		AccountID:    account.ID,
+		Type:         kind,
+		Amount:       amount,
+		BalanceAfter: account.Balance,
+		CreatedAt:    s.clock.Now().UTC(),
+	}
+	return update
+}
+
+// retryOnConflict runs fn again, up to maxConflictRetries times in all,
+// while it fails because another writer changed an account between fn
+// reading and updating it. fn must re-read everything it updates.
+func retryOnConflict(fn func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if !errors.Is(err, store.ErrVersionConflict) {
+			return err
+		}
+		if attempt == maxConflictRetries {
+			return ErrConcurrentUpdate
+		}
+	}
+}
+
+// checkVersion enforces a caller's expected version; zero accepts any.
+func checkVersion(account types.Account, version int64) error {
+	if version != 0 && account.Version != version {
+		return ErrVersionMismatch
+	}
+	return nil
+}
+
+// lock acquires the per-account locks for ids in ascending order and returns
+// a function releasing them.
+func (s *AccountService) lock(ids ...int64) (unlock func()) {
+	sorted := append([]int64(nil), ids...)
+	slices.Sort(sorted)
+
+	mutexes := make([]*sync.Mutex, 0, len(sorted))
+	for _, id := range sorted {
+		m, _ := s.locks.LoadOrStore(id, &sync.Mutex{})
+		mu := m.(*sync.Mutex)
+		mu.Lock()
+		mutexes = append(mutexes, mu)
+	}
+
+	return func() {
+		for i := len(mutexes) - 1; i >= 0; i-- {
+			mutexes[i].Unlock()
+		}
+	}
+}
+
+func validEmail(email string) bool {
+	addr, err := mail.ParseAddress(email)
+	return err == nil && addr.Address == email
+}
@@ -0,0 +1,276 @@
+package services_test
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"BankSystemGoLang/services"
+	"BankSystemGoLang/types"
+)
+
+func TestDepositConcurrent(t *testing.T) {
+	accounts, _, _ := newAccountService(t)
+	account := open(t, accounts, types.CreateAccountRequest{Email: "a@example.com"})
+
+	const deposits = 100
+	var wg sync.WaitGroup
+	for range deposits {
+		wg.Go(func() {
+			if _, err := accounts.Deposit(ctx, account.ID, 1_50, 0); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+	wg.Wait()
+
+	if got, want := balanceOf(t, accounts, account.ID), types.Money(deposits*1_50); got != want {
+		t.Errorf("balance = %s, want %s", got, want)
+	}
+}
+
+func TestTransferConcurrentReciprocal(t *testing.T) {
+	accounts, _, _ := newAccountService(t)
+	a := open(t, accounts, types.CreateAccountRequest{Email: "a@example.com", InitialBalance: 100_00})
+	b := open(t, accounts, types.CreateAccountRequest{Email: "b@example.com", InitialBalance: 100_00})
+
+	// Transfers in both directions at once deadlock unless both locks are
+	// always taken in the same order.
+	var wg sync.WaitGroup
+	for i := range 200 {
+		from, to, amount := a.ID, b.ID, types.Money(7_00)
+		if i%2 == 1 {
+			from, to, amount = b.ID, a.ID, 3_00
+		}
+		wg.Go(func() {
+			_, _, _, err := accounts.Transfer(ctx, from, to, amount)
+			if err != nil && !errors.Is(err, services.ErrInsufficientFunds) {
+				t.Error(err)
+			}
+		})
+	}
+	wg.Wait()
+
+	if total := balanceOf(t, accounts, a.ID) + balanceOf(t, accounts, b.ID); total != 200_00 {
+		t.Errorf("total balance = %s, want 200.00", total)
+	}
+}
+
+func TestLedgerMatchesBalance(t *testing.T) {
+	accounts, _, _ := newAccountService(t)
+	a := open(t, accounts, types.CreateAccountRequest{Email: "a@example.com", InitialBalance: 100_00})
+	b := open(t, accounts, types.CreateAccountRequest{Email: "b@example.com"})
+
+	steps := []func() error{
+		func() error { _, err := accounts.Deposit(ctx, a.ID, 20_00, 0); return err },
+		func() error { _, err := accounts.Withdraw(ctx, a.ID, 30_00, 0); return err },
+		func() error { _, _, _, err := accounts.Transfer(ctx, a.ID, b.ID, 45_50); return err },
+		func() error { _, err := accounts.Deposit(ctx, b.ID, 4_50, 0); return err },
+		func() error { _, _, _, err := accounts.Transfer(ctx, b.ID, a.ID, 10_00); return err },
+	}
+	for i, step := range steps {
+		if err := step(); err != nil {
+			t.Fatalf("step %d: %v", i, err)
+		}
+	}
+
+	for _, id := range []int64{a.ID, b.ID} {
+		page, err := accounts.Transactions(ctx, id, 100, 0, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Entries are newest first; replay them oldest first.
+		var sum types.Money
+		for i := len(page.Transactions) - 1; i >= 0; i-- {
+			entry := page.Transactions[i]
+			switch entry.Type {
+			case types.TransactionWithdrawal, types.TransactionTransferOut:
+				sum -= entry.Amount
+			default:
+				sum += entry.Amount
+			}
+			if entry.BalanceAfter != sum {
+				t.Errorf("account %d entry %d: balance_after = %s, want running total %s", id, entry.ID, entry.BalanceAfter, sum)
+			}
+		}
+		if got := balanceOf(t, accounts, id); got != sum {
+			t.Errorf("account %d: balance = %s, ledger sums to %s", id, got, sum)
+		}
+	}
+	if got := balanceOf(t, accounts, a.ID); got != 54_50 {
+		t.Errorf("a balance = %s, want 54.50", got)
+	}
+}
+
+func TestListFiltersAndClamps(t *testing.T) {
+	accounts, _, _ := newAccountService(t)
+	for i := range 3 {
+		open(t, accounts, types.CreateAccountRequest{Email: fmt.Sprintf("user%d@example.com", i)})
+	}
+	if _, err := accounts.Close(ctx, 2, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		status    types.AccountStatus
+		limit     int
+		wantLimit int
+		wantIDs   []int64
+		wantTotal int
+	}{
+		{name: "all", limit: 10, wantLimit: 10, wantIDs: []int64{1, 2, 3}, wantTotal: 3},
+		{name: "open", status: types.AccountOpen, limit: 10, wantLimit: 10, wantIDs: []int64{1, 3}, wantTotal: 2},
+		{name: "closed", status: types.AccountClosed, limit: 10, wantLimit: 10, wantIDs: []int64{2}, wantTotal: 1},
+		{name: "zero limit", limit: 0, wantLimit: 1, wantIDs: []int64{1}, wantTotal: 3},
+		{name: "oversized limit", limit: 500, wantLimit: services.MaxAccountLimit, wantIDs: []int64{1, 2, 3}, wantTotal: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := accounts.List(ctx, 1, tt.status, tt.limit, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var ids []int64
+			for _, a := range page.Accounts {
+				ids = append(ids, a.ID)
+			}
+			if page.Limit != tt.wantLimit || page.Total != tt.wantTotal || !slices.Equal(ids, tt.wantIDs) {
+				t.Errorf("got IDs %v of %d at limit %d, want %v of %d at limit %d",
+					ids, page.Total, page.Limit, tt.wantIDs, tt.wantTotal, tt.wantLimit)
+			}
+		})
+	}
+
+	if _, err := accounts.List(ctx, 1, "frozen", 10, 0); !errors.Is(err, services.ErrInvalidStatus) {
+		t.Errorf("unknown status: err = %v, want ErrInvalidStatus", err)
+	}
+}
+
+func TestAccrueInterestOncePerDay(t *testing.T) {
+	accounts, _, clk := newAccountService(t)
+	account := open(t, accounts, types.CreateAccountRequest{Email: "s@example.com", AccountType: types.AccountSavings, InitialBalance: 1000_00})
+
+	clk.Advance(10 * 24 * time.Hour)
+	first, err := accounts.AccrueInterest(ctx, account.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 1000.00 at 2% for 10 of 365 days is 0.5479..., rounded to 0.55.
+	if first.Days != 10 || first.Interest != 55 || first.Balance != 1000_55 {
+		t.Fatalf("first accrual = %+v, want 10 days earning 0.55", first)
+	}
+
+	clk.Advance(3 * time.Hour)
+	second, err := accounts.AccrueInterest(ctx, account.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.Days != 0 || second.Interest != 0 || second.Balance != 1000_55 {
+		t.Errorf("second accrual on the same day = %+v, want nothing posted", second)
+	}
+
+	page, err := accounts.Transactions(ctx, account.ID, 10, 0, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	interest := 0
+	for _, entry := range page.Transactions {
+		if entry.Type == types.TransactionInterest {
+			interest++
+		}
+	}
+	if interest != 1 {
+		t.Errorf("ledger has %d interest entries, want 1", interest)
+	}
+
+	if _, err := accounts.AccrueInterest(ctx, open(t, accounts, types.CreateAccountRequest{Email: "c@example.com"}).ID); !errors.Is(err, services.ErrNotSavings) {
+		t.Errorf("checking account: err = %v, want ErrNotSavings", err)
+	}
+}
+
+func TestAccrueInterestCarriesDaysRoundedToZero(t *testing.T) {
+	accounts, _, clk := newAccountService(t)
+	account := open(t, accounts, types.CreateAccountRequest{Email: "s@example.com", AccountType: types.AccountSavings, InitialBalance: 10_00})
+
+	// 10.00 at 2% earns 0.00055 a day, so a single day rounds to nothing.
+	clk.Advance(24 * time.Hour)
+	first, err := accounts.AccrueInterest(ctx, account.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Days != 1 || first.Interest != 0 || first.Balance != 10_00 {
+		t.Fatalf("first accrual = %+v, want 1 day earning nothing", first)
+	}
+
+	// The unpaid day is counted again: 10 days earn 0.0055, rounded to 0.01.
+	clk.Advance(9 * 24 * time.Hour)
+	second, err := accounts.AccrueInterest(ctx, account.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.Days != 10 || second.Interest != 1 || second.Balance != 10_01 {
+		t.Errorf("second accrual = %+v, want 10 days earning 0.01", second)
+	}
+}
+
+func TestWithdrawOverdraft(t *testing.T) {
+	accounts, _, _ := newAccountService(t)
+	account := open(t, accounts, types.CreateAccountRequest{Email: "a@example.com", InitialBalance: 50_00, OverdraftLimit: 100_00})
+
+	updated, err := accounts.Withdraw(ctx, account.ID, 120_00, 0)
+	if err != nil {
+		t.Fatalf("withdrawal into the overdraft: %v", err)
+	}
+	if updated.Balance != -70_00 {
+		t.Errorf("balance = %s, want -70.00", updated.Balance)
+	}
+
+	// 30.00 of overdraft is left, so 30.01 is too much.
+	got, err := accounts.Withdraw(ctx, account.ID, 30_01, 0)
+	if !errors.Is(err, services.ErrInsufficientFunds) {
+		t.Fatalf("withdrawal past the overdraft: err = %v, want ErrInsufficientFunds", err)
+	}
+	if got.Balance != -70_00 {
+		t.Errorf("reported balance = %s, want -70.00", got.Balance)
+	}
+	if _, err := accounts.Withdraw(ctx, account.ID, 30_00, 0); err != nil {
+		t.Errorf("withdrawal up to the overdraft limit: %v", err)
+	}
+	if got := balanceOf(t, accounts, account.ID); got != -100_00 {
+		t.Errorf("balance = %s, want -100.00", got)
+	}
+}
+
+func TestDailyLimitResetsAtUTCMidnight(t *testing.T) {
+	accounts, _, clk := newAccountService(t)
+	account := open(t, accounts, types.CreateAccountRequest{Email: "a@example.com", InitialBalance: 500_00, DailyWithdrawalLimit: 100_00})
+
+	// Half an hour before midnight UTC, on a clock whose local zone is
+	// already in the next day, so only the UTC day can make this pass.
+	clk.Set(time.Date(2025, time.March, 10, 23, 30, 0, 0, time.UTC).In(time.FixedZone("UTC+2", 2*60*60)))
+	if _, err := accounts.Withdraw(ctx, account.ID, 60_00, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := accounts.Withdraw(ctx, account.ID, 40_00, 0); err != nil {
+		t.Fatal(err)
+	}
+	var limitErr *services.DailyLimitError
+	if _, err := accounts.Withdraw(ctx, account.ID, 1, 0); !errors.As(err, &limitErr) || limitErr.Remaining != 0 {
+		t.Fatalf("withdrawing past the limit: err = %v, want a DailyLimitError with nothing remaining", err)
+	}
+
+	clk.Advance(31 * time.Minute)
+	if _, err := accounts.Withdraw(ctx, account.ID, 100_00, 0); err != nil {
+		t.Fatalf("first withdrawal of the new UTC day: %v", err)
+	}
+	if _, err := accounts.Withdraw(ctx, account.ID, 1, 0); !errors.As(err, &limitErr) {
+		t.Errorf("past the new day's limit: err = %v, want a DailyLimitError", err)
+	}
+	if got := balanceOf(t, accounts, account.ID); got != 300_00 {
+		t.Errorf("balance = %s, want 300.00", got)
+	}
+}
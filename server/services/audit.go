@@ -0,0 +1,56 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"BankSystemGoLang/clock"
+	"BankSystemGoLang/store"
+	"BankSystemGoLang/types"
+)
+
+// DefaultAuditDays is the audit log period listed when the caller gives
+// no dates.
+const DefaultAuditDays = 30
+
+// AuditService records admin actions in the append-only audit log and
+// lists them for compliance review.
+type AuditService struct {
+	store store.Store
+	clock clock.Clock
+}
+
+func NewAuditService(s store.Store, clk clock.Clock) *AuditService {
+	return &AuditService{store: s, clock: clk}
+}
+
+// Record appends an entry saying adminID applied action to accountID.
+// request is the JSON body of the admin's request, or nil.
+func (s *AuditService) Record(ctx context.Context, adminID int64, action types.AuditAction, accountID int64, request json.RawMessage) (types.AuditEntry, error) {
+	entry := types.AuditEntry{
+		AdminID:   adminID,
+		Action:    action,
+		AccountID: accountID,
+		Request:   request,
+		CreatedAt: s.clock.Now().UTC(),
+	}
+	if err := s.store.CreateAuditEntry(ctx, &entry); err != nil {
+		return types.AuditEntry{}, err
+	}
+	return entry, nil
+}
+
+// List returns the entries recorded from the start of the from day to the
+// end of the to day.
+func (s *AuditService) List(ctx context.Context, from, to time.Time) (types.AuditLog, error) {
+	from, to = startOfDay(from), startOfDay(to)
+	if from.After(to) {
+		return types.AuditLog{}, ErrInvalidDateRange
+	}
+	entries, err := s.store.ListAuditEntries(ctx, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		return types.AuditLog{}, err
+	}
+	return types.AuditLog{Entries: entries, From: from, To: to}, nil
+}
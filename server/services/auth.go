@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/crypto/bcrypt"
+
+	"BankSystemGoLang/clock"
+	"BankSystemGoLang/store"
+	"BankSystemGoLang/types"
+)
+
+var (
+	ErrInvalidCredentials = errors.New("invalid email or password")
+	ErrTokenExpired       = errors.New("token has expired")
+	ErrInvalidToken       = errors.New("token is invalid")
+	ErrPasswordTooShort   = fmt.Errorf("password must be at least %d characters", MinPasswordLength)
+	ErrUserExists         = errors.New("a user with this email already exists")
+	ErrEmailNotVerified   = errors.New("email address has not been verified, follow the link sent at registration")
+)
+
+const (
+	// TokenTTL is how long an issued access token stays valid.
+	TokenTTL = time.Hour
+	// MinPasswordLength is the shortest password accepted at registration.
+	MinPasswordLength = 8
+)
+
+// AuthService checks user credentials and issues and verifies HS256 JWTs.
+// Users registering with one of adminEmails are given the admin role.
+// Tokens are issued and checked against its clock.
+type AuthService struct {
+	store       store.Store
+	secret      []byte
+	adminEmails []string
+	clock       clock.Clock
+}
+
+func NewAuthService(s store.Store, secret string, adminEmails []string, clk clock.Clock) *AuthService {
+	return &AuthService{store: s, secret: []byte(secret), adminEmails: adminEmails, clock: clk}
+}
+
+// Register creates a user, storing only the bcrypt hash of the password.
+func (s *AuthService) Register(ctx context.Context, req types.RegisterRequest) (types.User, error) {
+	if !validEmail(req.Email) {
+		return types.User{}, ErrInvalidEmail
+	}
+	if len(req.Password) < MinPasswordLength {
+		return types.User{}, ErrPasswordTooShort
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return types.User{}, err
+	}
+
+	role := types.RoleUser
+	if slices.ContainsFunc(s.adminEmails, func(admin string) bool { return strings.EqualFold(admin, req.Email) }) {
+		role = types.RoleAdmin
+	}
+
+	user := types.User{
+		Email:        req.Email,
+		PasswordHash: string(hash),
+		Role:         role,
+		CreatedAt:    s.clock.Now().UTC(),
+	}
+	err = s.store.CreateUser(ctx, &user)
+	if errors.Is(err, store.ErrDuplicateEmail) {
+		return types.User{}, ErrUserExists
+	}
+	if err != nil {
+		return types.User{}, err
+	}
+	return user, nil
+}
+
+// Login verifies the credentials and returns a signed token for the user.
+// Users who have not verified their email yet get ErrEmailNotVerified, but
+// only once their password has been checked.
+func (s *AuthService) Login(ctx context.Context, req types.LoginRequest) (types.LoginResponse, error) {
+	user, err := s.store.GetUserByEmail(ctx, req.Email)
+	if errors.Is(err, store.ErrNotFound) {
+		return types.LoginResponse{}, ErrInvalidCredentials
+	}
+	if err != nil {
+		return types.LoginResponse{}, err
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)) != nil {
+		return types.LoginResponse{}, ErrInvalidCredentials
+	}
+	if user.VerifiedAt == nil {
+		return types.LoginResponse{}, ErrEmailNotVerified
+	}
+
+	return s.issue(user, s.clock.Now())
+}
+
+// claims are the JWT claims of an access token. The role is read from the
+// token rather than the store, so a role change applies from the user's
+// next login.
+type claims struct {
+	Role types.Role `json:"role,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// ParseToken validates a signed token and returns the user ID it was issued
+// for and the role it grants. Tokens without a role claim grant the user
+// role.
+func (s *AuthService) ParseToken(raw string) (int64, types.Role, error) {
+	var c claims
+	_, err := jwt.ParseWithClaims(raw, &c, func(*jwt.Token) (any, error) {
+		return s.secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired(), jwt.WithTimeFunc(s.clock.Now))
+	if errors.Is(err, jwt.ErrTokenExpired) {
+		return 0, "", ErrTokenExpired
+	}
+	if err != nil {
+		return 0, "", ErrInvalidToken
+	}
+
+	userID, err := strconv.ParseInt(c.Subject, 10, 64)
+	if err != nil {
+		return 0, "", ErrInvalidToken
+	}
+	switch c.Role {
+	case "":
+		c.Role = types.RoleUser
+	case types.RoleUser, types.RoleAdmin:
+	default:
+		return 0, "", ErrInvalidToken
+	}
+	return userID, c.Role, nil
+}
+
+func (s *AuthService) issue(user types.User, now time.Time) (types.LoginResponse, error) {
+	expiresAt := now.Add(TokenTTL)
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims{
+		Role: user.Role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   strconv.FormatInt(user.ID, 10),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	})
+
+	signed, err := token.SignedString(s.secret)
+	if err != nil {
+		return types.LoginResponse{}, err
+	}
+	return types.LoginResponse{Token: signed, ExpiresAt: expiresAt.UTC()}, nil
+}
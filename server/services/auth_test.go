@@ -0,0 +1,96 @@
+package services_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"BankSystemGoLang/clock"
+	"BankSystemGoLang/services"
+	"BankSystemGoLang/store"
+	"BankSystemGoLang/types"
+)
+
+const testSecret = "services-test-secret-of-32-bytes"
+
+func TestRegisterStoresOnlyTheHash(t *testing.T) {
+	const password = "plaintext-password-42"
+	path := filepath.Join(t.TempDir(), "bank.db")
+	st, err := store.OpenSQLite(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth := services.NewAuthService(st, testSecret, nil, clock.Real{})
+
+	user, err := auth.Register(ctx, types.RegisterRequest{Email: "a@example.com", Password: password})
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := json.Marshal(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(body, []byte(password)) || bytes.Contains(body, []byte(user.PasswordHash)) {
+		t.Errorf("registered user serialises its password: %s", body)
+	}
+
+	stored, err := st.GetUserByEmail(ctx, "a@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.PasswordHash == password {
+		t.Fatal("stored password hash is the plaintext")
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(stored.PasswordHash), []byte(password)); err != nil {
+		t.Errorf("stored hash does not verify against the password: %v", err)
+	}
+
+	if err := st.Close(); err != nil {
+		t.Fatal(err)
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(raw, []byte(password)) {
+		t.Error("database file contains the plaintext password")
+	}
+}
+
+func TestTokenExpiresOnTheServiceClock(t *testing.T) {
+	st := store.NewMemoryStore()
+	clk := clock.NewMock(testStart)
+	auth := services.NewAuthService(st, testSecret, nil, clk)
+	user, err := auth.Register(ctx, types.RegisterRequest{Email: "a@example.com", Password: "correct horse battery"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !user.CreatedAt.Equal(testStart) {
+		t.Errorf("created at %s, want the clock's %s", user.CreatedAt, testStart)
+	}
+	if err := st.MarkUserVerified(ctx, user.ID, testStart); err != nil {
+		t.Fatal(err)
+	}
+	res, err := auth.Login(ctx, types.LoginRequest{Email: "a@example.com", Password: "correct horse battery"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := testStart.Add(services.TokenTTL); !res.ExpiresAt.Equal(want) {
+		t.Errorf("expires at %s, want %s", res.ExpiresAt, want)
+	}
+
+	clk.Advance(services.TokenTTL - time.Second)
+	if id, _, err := auth.ParseToken(res.Token); err != nil || id != user.ID {
+		t.Fatalf("just before expiry: user %d, err %v, want user %d", id, err, user.ID)
+	}
+	clk.Advance(2 * time.Second)
+	if _, _, err := auth.ParseToken(res.Token); !errors.Is(err, services.ErrTokenExpired) {
+		t.Errorf("just after expiry: err = %v, want ErrTokenExpired", err)
+	}
+}
@@ -0,0 +1,149 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"BankSystemGoLang/store"
+	"BankSystemGoLang/types"
+)
+
+// MaxBatchSize caps the number of transfers in one batch.
+const MaxBatchSize = 100
+
+var (
+	ErrEmptyBatch    = errors.New("a batch needs at least one transfer")
+	ErrBatchTooLarge = fmt.Errorf("a batch may hold at most %d transfers", MaxBatchSize)
+	ErrBatchFailed   = errors.New("a transfer in the batch failed, so none were applied")
+)
+
+// BatchOutcome reports one transfer of a batch. Err is only set on the
+// item that failed; FX is set on cross-currency items that were quoted.
+type BatchOutcome struct {
+	Status types.BatchItemStatus
+	Err    error
+	FX     *Conversion
+}
+
+// TransferBatch applies the transfers in order as one all-or-nothing
+// operation: each item is checked against the balances left by the items
+// before it, and the whole batch is read and written in a single store
+// transaction. When
+// an item fails nothing is written and the returned error wraps both
+// ErrBatchFailed and the item's own error, which the outcomes attribute to
+// it.
+func (s *AccountService) TransferBatch(ctx context.Context, items []types.TransferRequest) ([]BatchOutcome, error) {
+	if len(items) == 0 {
+		return nil, ErrEmptyBatch
+	}
+	if len(items) > MaxBatchSize {
+		return nil, ErrBatchTooLarge
+	}
+
+	// Validate and quote every item before anything is locked, as Transfer
+	// does for a single one.
+	outcomes := make([]BatchOutcome, len(items))
+	var ids []int64
+	for i, item := range items {
+		fx, err := s.prepare(ctx, item)
+		if err != nil {
+			return failBatch(outcomes, i, err)
+		}
+		outcomes[i].FX = fx
+		ids = append(ids, item.FromID, item.ToID)
+	}
+
+	unlock := s.lock(unique(ids)...)
+	defer unlock()
+
+	failed := -1
+	err := retryOnConflict(func() error {
+		return s.store.Atomic(ctx, func(tx store.Tx) error {
+			accounts := map[int64]types.Account{}
+			for _, id := range ids {
+				if _, ok := accounts[id]; ok {
+					continue
+				}
+				account, err := loadAccount(ctx, tx, id)
+				if err != nil {
+					return err
+				}
+				accounts[id] = account
+			}
+
+			var updates []store.BalanceUpdate
+			for i, item := range items {
+				from, to := accounts[item.FromID], accounts[item.ToID]
+				out, in, err := s.move(&from, &to, item.Amount, outcomes[i].FX)
+				if err != nil {
+					failed = i
+					return err
+				}
+				accounts[item.FromID], accounts[item.ToID] = from, to
+				updates = append(updates, out, in)
+			}
+			return tx.UpdateBalance(ctx, updates...)
+		})
+	})
+	if failed >= 0 {
+		return failBatch(outcomes, failed, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for i, item := range items {
+		outcomes[i].Status = types.BatchItemApplied
+		s.publish(types.Event{Type: types.EventTransfer, AccountID: item.FromID, ToAccountID: item.ToID, Amount: item.Amount})
+	}
+	return outcomes, nil
+}
+
+// prepare runs the checks Transfer makes before locking and returns the
+// item's conversion, if it needs one.
+func (s *AccountService) prepare(ctx context.Context, item types.TransferRequest) (*Conversion, error) {
+	if item.FromID == item.ToID {
+		return nil, ErrSameAccount
+	}
+	if item.Amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+	from, err := s.Get(ctx, item.FromID)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.Get(ctx, item.ToID)
+	if err != nil {
+		return nil, err
+	}
+	return s.quote(from, to, item.Amount)
+}
+
+// failBatch marks item failed because of err, the items before it rolled
+// back and the ones after it skipped.
+func failBatch(outcomes []BatchOutcome, failed int, err error) ([]BatchOutcome, error) {
+	for i := range outcomes {
+		switch {
+		case i < failed:
+			outcomes[i].Status = types.BatchItemRolledBack
+		case i == failed:
+			outcomes[i].Status, outcomes[i].Err = types.BatchItemFailed, err
+		default:
+			outcomes[i].Status, outcomes[i].FX = types.BatchItemSkipped, nil
+		}
+	}
+	return outcomes, fmt.Errorf("%w: transfer %d: %w", ErrBatchFailed, failed, err)
+}
+
+func unique(ids []int64) []int64 {
+	seen := make(map[int64]bool, len(ids))
+	var out []int64
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			out = append(out, id)
+		}
+	}
+	return out
+}
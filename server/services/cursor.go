@@ -0,0 +1,60 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+
+	"BankSystemGoLang/types"
+)
+
+var ErrInvalidCursor = errors.New("cursor is not one returned by this API")
+
+// cursorPrefix versions the cursor format so it can change without old
+// cursors being misread.
+const cursorPrefix = "tx2:"
+
+// encodeCursor returns the opaque cursor that resumes a listing of the
+// account's ledger after tx. It carries a MAC over both IDs, so a client
+// can neither forge one nor reuse it on another account.
+func (s *AccountService) encodeCursor(accountID int64, tx types.Transaction) string {
+	payload := cursorPrefix + strconv.FormatInt(tx.ID, 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." +
+		base64.RawURLEncoding.EncodeToString(s.cursorMAC(accountID, tx.ID))
+}
+
+// decodeCursor returns the ID of the last entry the cursor's page held,
+// after checking it was issued for this account.
+func (s *AccountService) decodeCursor(accountID int64, cursor string) (int64, error) {
+	encoded, encodedMAC, ok := strings.Cut(cursor, ".")
+	if !ok {
+		return 0, ErrInvalidCursor
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return 0, ErrInvalidCursor
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(encodedMAC)
+	if err != nil {
+		return 0, ErrInvalidCursor
+	}
+	digits, ok := strings.CutPrefix(string(raw), cursorPrefix)
+	if !ok {
+		return 0, ErrInvalidCursor
+	}
+	id, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil || id <= 0 || !hmac.Equal(mac, s.cursorMAC(accountID, id)) {
+		return 0, ErrInvalidCursor
+	}
+	return id, nil
+}
+
+// cursorMAC authenticates a cursor with the server secret.
+func (s *AccountService) cursorMAC(accountID, txID int64) []byte {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(cursorPrefix + strconv.FormatInt(accountID, 10) + ":" + strconv.FormatInt(txID, 10)))
+	return mac.Sum(nil)[:16]
+}
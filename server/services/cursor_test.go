@@ -0,0 +1,85 @@
+package services_test
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+
+	"BankSystemGoLang/services"
+	"BankSystemGoLang/types"
+)
+
+func TestTransactionCursorPages(t *testing.T) {
+	accounts, _, _ := newAccountService(t)
+	account := open(t, accounts, types.CreateAccountRequest{Email: "a@example.com", InitialBalance: 1_00})
+	for range 5 {
+		if _, err := accounts.Deposit(ctx, account.ID, 1_00, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var (
+		seen   []int64
+		cursor string
+	)
+	for page := 1; ; page++ {
+		got, err := accounts.Transactions(ctx, account.ID, 2, 0, cursor)
+		if err != nil {
+			t.Fatalf("page %d: %v", page, err)
+		}
+		for _, tx := range got.Transactions {
+			if len(seen) > 0 && tx.ID >= seen[len(seen)-1] {
+				t.Fatalf("page %d: entry %d after %d, want strictly older entries", page, tx.ID, seen[len(seen)-1])
+			}
+			seen = append(seen, tx.ID)
+		}
+		if got.NextCursor == "" {
+			if page != 3 {
+				t.Errorf("listing ended on page %d, want 3", page)
+			}
+			break
+		}
+		if page == 3 {
+			t.Fatalf("page 3 has a next cursor, want it to be the last")
+		}
+		cursor = got.NextCursor
+	}
+	if len(seen) != 6 {
+		t.Errorf("saw %d entries, want all 6", len(seen))
+	}
+}
+
+func TestTransactionCursorRejectsTampering(t *testing.T) {
+	accounts, _, _ := newAccountService(t)
+	a := open(t, accounts, types.CreateAccountRequest{Email: "a@example.com", InitialBalance: 1_00})
+	b := open(t, accounts, types.CreateAccountRequest{Email: "b@example.com", InitialBalance: 1_00})
+	for range 3 {
+		if _, err := accounts.Deposit(ctx, a.ID, 1_00, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	page, err := accounts.Transactions(ctx, a.ID, 2, 0, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, mac, _ := strings.Cut(page.NextCursor, ".")
+
+	tests := []struct {
+		name      string
+		accountID int64
+		cursor    string
+	}{
+		{name: "other entry ID", accountID: a.ID, cursor: base64.RawURLEncoding.EncodeToString([]byte("tx2:99")) + "." + mac},
+		{name: "other account", accountID: b.ID, cursor: page.NextCursor},
+		{name: "unsigned", accountID: a.ID, cursor: base64.RawURLEncoding.EncodeToString([]byte("tx1:3"))},
+		{name: "garbage", accountID: a.ID, cursor: "not a cursor"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := accounts.Transactions(ctx, tt.accountID, 2, 0, tt.cursor); !errors.Is(err, services.ErrInvalidCursor) {
+				t.Errorf("err = %v, want ErrInvalidCursor", err)
+			}
+		})
+	}
+}
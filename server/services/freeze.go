@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"BankSystemGoLang/types"
+)
+
+var (
+	ErrAlreadyFrozen = errors.New("account is already frozen")
+	ErrNotFrozen     = errors.New("account is not frozen")
+	ErrReasonMissing = errors.New("a reason is required")
+)
+
+// Freeze stops all money movement on the account until it is unfrozen;
+// reads keep working. The reason is recorded in a freeze ledger entry.
+func (s *AccountService) Freeze(ctx context.Context, id int64, reason string) (types.Account, error) {
+	return s.setFrozen(ctx, id, true, reason)
+}
+
+// Unfreeze lifts a freeze, recording the reason in an unfreeze ledger
+// entry.
+func (s *AccountService) Unfreeze(ctx context.Context, id int64, reason string) (types.Account, error) {
+	return s.setFrozen(ctx, id, false, reason)
+}
+
+func (s *AccountService) setFrozen(ctx context.Context, id int64, frozen bool, reason string) (types.Account, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return types.Account{}, ErrReasonMissing
+	}
+	if _, err := s.Get(ctx, id); err != nil {
+		return types.Account{}, err
+	}
+
+	unlock := s.lock(id)
+	defer unlock()
+
+	kind := types.TransactionUnfreeze
+	if frozen {
+		kind = types.TransactionFreeze
+	}
+
+	var account types.Account
+	err := retryOnConflict(func() (err error) {
+		account, err = s.Get(ctx, id)
+		if err != nil {
+			return err
+		}
+		switch {
+		case account.Status == types.AccountClosed:
+			return ErrAccountClosed
+		case frozen && account.Frozen:
+			return ErrAlreadyFrozen
+		case !frozen && !account.Frozen:
+			return ErrNotFrozen
+		}
+
+		account.Frozen = frozen
+		update := s.ledgerUpdate(account, kind, 0)
+		update.Entry.Reason = reason
+		if err := s.store.UpdateBalance(ctx, update); err != nil {
+			return err
+		}
+		account.Version++
+		return nil
+	})
+	if err != nil {
+		return types.Account{}, err
+	}
+	return account, nil
+}
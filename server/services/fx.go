@@ -0,0 +1,57 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+var (
+	ErrExchangeRate    = errors.New("exchange rate unavailable")
+	ErrRateUnavailable = errors.New("no rate configured")
+)
+
+// RateProvider returns how many units of quote currency one unit of base
+// currency buys. Implementations may call out to an external service.
+type RateProvider interface {
+	Rate(base, quote string) (*big.Rat, error)
+}
+
+// StaticRates is a RateProvider backed by a fixed table keyed "BASE/QUOTE".
+// The inverse of a configured pair is derived, so "EUR/USD" also serves
+// USD to EUR.
+type StaticRates map[string]*big.Rat
+
+func (r StaticRates) Rate(base, quote string) (*big.Rat, error) {
+	if base == quote {
+		return big.NewRat(1, 1), nil
+	}
+	if rate, ok := r[base+"/"+quote]; ok {
+		return new(big.Rat).Set(rate), nil
+	}
+	if rate, ok := r[quote+"/"+base]; ok {
+		return new(big.Rat).Inv(rate), nil
+	}
+	return nil, fmt.Errorf("%w for %s/%s", ErrRateUnavailable, base, quote)
+}
+
+// ParseRates reads a comma-separated list of pairs such as
+// "EUR/USD=1.08,GBP/USD=1.27" into StaticRates.
+func ParseRates(s string) (StaticRates, error) {
+	rates := StaticRates{}
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		pair, value, ok := strings.Cut(item, "=")
+		base, quote, okPair := strings.Cut(pair, "/")
+		rate, okRate := new(big.Rat).SetString(value)
+		if !ok || !okPair || len(base) != 3 || len(quote) != 3 || !okRate || rate.Sign() <= 0 {
+			return nil, fmt.Errorf("invalid exchange rate %q, want BASE/QUOTE=rate such as EUR/USD=1.08", item)
+		}
+		rates[strings.ToUpper(base)+"/"+strings.ToUpper(quote)] = rate
+	}
+	return rates, nil
+}
@@ -0,0 +1,88 @@
+package services_test
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"BankSystemGoLang/clock"
+	"BankSystemGoLang/services"
+	"BankSystemGoLang/store"
+	"BankSystemGoLang/types"
+)
+
+// stubRates quotes rate for every pair, or fails with err when it is set.
+type stubRates struct {
+	rate *big.Rat
+	err  error
+}
+
+func (r stubRates) Rate(base, quote string) (*big.Rat, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	return r.rate, nil
+}
+
+func TestTransferConvertsCurrency(t *testing.T) {
+	accounts := services.NewAccountService(store.NewMemoryStore(), testSecret, nil, stubRates{rate: big.NewRat(10837, 10000)}, clock.NewMock(testStart))
+	eur := open(t, accounts, types.CreateAccountRequest{Email: "eur@example.com", Currency: "EUR", InitialBalance: 200_00})
+	usd := open(t, accounts, types.CreateAccountRequest{Email: "usd@example.com", Currency: "USD"})
+
+	from, to, fx, err := accounts.Transfer(ctx, eur.ID, usd.ID, 100_00)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fx == nil || fx.Rate != "1.0837" || fx.Credited != 108_37 {
+		t.Fatalf("conversion = %+v, want 100.00 EUR credited as 108.37 USD at 1.0837", fx)
+	}
+	if from.Balance != 100_00 || to.Balance != 108_37 {
+		t.Errorf("balances = %s EUR and %s USD, want 100.00 and 108.37", from.Balance, to.Balance)
+	}
+
+	page, err := accounts.Transactions(ctx, eur.ID, 1, 0, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := page.Transactions[0]
+	if out.Type != types.TransactionTransferOut || out.Amount != 100_00 || out.ExchangeRate != "1.0837" ||
+		out.ConvertedAmount == nil || *out.ConvertedAmount != 108_37 {
+		t.Errorf("debit entry = %+v, want 100.00 converted to 108.37 at 1.0837", out)
+	}
+	page, err = accounts.Transactions(ctx, usd.ID, 1, 0, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if in := page.Transactions[0]; in.Amount != 108_37 || in.ExchangeRate != "1.0837" {
+		t.Errorf("credit entry = %+v, want 108.37 at 1.0837", in)
+	}
+}
+
+func TestTransferRateProviderFailure(t *testing.T) {
+	accounts := services.NewAccountService(store.NewMemoryStore(), testSecret, nil, stubRates{err: errors.New("rates service timed out")}, clock.NewMock(testStart))
+	eur := open(t, accounts, types.CreateAccountRequest{Email: "eur@example.com", Currency: "EUR", InitialBalance: 200_00})
+	usd := open(t, accounts, types.CreateAccountRequest{Email: "usd@example.com", Currency: "USD"})
+
+	if _, _, _, err := accounts.Transfer(ctx, eur.ID, usd.ID, 100_00); !errors.Is(err, services.ErrExchangeRate) {
+		t.Fatalf("err = %v, want ErrExchangeRate", err)
+	}
+	if got := balanceOf(t, accounts, eur.ID); got != 200_00 {
+		t.Errorf("source balance = %s, want 200.00", got)
+	}
+	if got := balanceOf(t, accounts, usd.ID); got != 0 {
+		t.Errorf("destination balance = %s, want 0.00", got)
+	}
+	page, err := accounts.Transactions(ctx, usd.ID, 10, 0, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Transactions) != 0 {
+		t.Errorf("destination ledger = %+v, want it empty", page.Transactions)
+	}
+
+	// Accounts sharing a currency need no rate.
+	other := open(t, accounts, types.CreateAccountRequest{Email: "eur2@example.com", Currency: "EUR"})
+	if _, _, _, err := accounts.Transfer(ctx, eur.ID, other.ID, 1_00); err != nil {
+		t.Errorf("same-currency transfer: %v", err)
+	}
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"BankSystemGoLang/store"
+	"BankSystemGoLang/types"
+)
+
+var (
+	ErrHoldNotFound  = errors.New("hold not found")
+	ErrHoldNotActive = errors.New("hold has already been captured or released")
+)
+
+// PlaceHold reserves amount of the account's available balance, which must
+// cover it just as it would a withdrawal, and of its daily withdrawal
+// limit, failing with a *DailyLimitError when the day has too little left.
+// The posted balance is unchanged and no ledger entry is written until the
+// hold is captured. On ErrInsufficientFunds the untouched account is
+// returned.
+func (s *AccountService) PlaceHold(ctx context.Context, accountID int64, amount types.Money) (types.Hold, types.Account, error) {
+	if amount <= 0 {
+		return types.Hold{}, types.Account{}, ErrInvalidAmount
+	}
+	if _, err := s.Get(ctx, accountID); err != nil {
+		return types.Hold{}, types.Account{}, err
+	}
+
+	unlock := s.lock(accountID)
+	defer unlock()
+
+	var (
+		hold    types.Hold
+		account types.Account
+	)
+	err := retryOnConflict(func() (err error) {
+		account, err = s.Get(ctx, accountID)
+		if err != nil {
+			return err
+		}
+		if err := movable(account); err != nil {
+			return err
+		}
+		if !covers(account, amount) {
+			return ErrInsufficientFunds
+		}
+		now := s.clock.Now().UTC()
+		remaining, err := dailyRemaining(ctx, s.store, account, now)
+		if err != nil {
+			return err
+		}
+		if amount > remaining {
+			return &DailyLimitError{Limit: account.DailyWithdrawalLimit, Remaining: remaining}
+		}
+
+		account.Held += amount
+		hold = types.Hold{
+			AccountID: accountID,
+			Amount:    amount,
+			Status:    types.HoldActive,
+			CreatedAt: now,
+		}
+		if err := s.store.CreateHold(ctx, &hold, balanceUpdate(account)); err != nil {
+			return err
+		}
+		account.Version++
+		return nil
+	})
+	if errors.Is(err, ErrInsufficientFunds) {
+		return types.Hold{}, account, err
+	}
+	if err != nil {
+		return types.Hold{}, types.Account{}, err
+	}
+	return hold, account, nil
+}
+
+// GetHold returns the hold with the given ID.
+func (s *AccountService) GetHold(ctx context.Context, id int64) (types.Hold, error) {
+	hold, err := s.store.GetHold(ctx, id)
+	if errors.Is(err, store.ErrNotFound) {
+		return types.Hold{}, ErrHoldNotFound
+	}
+	return hold, err
+}
+
+// CaptureHold finalises an active hold: its amount is debited from the
+// posted balance with a capture ledger entry and stops being reserved. The
+// funds were set aside when the hold was placed, so this never fails for
+// lack of them, but the capture counts towards the daily withdrawal limit
+// of the day it happens on and fails with a *DailyLimitError past it.
+func (s *AccountService) CaptureHold(ctx context.Context, id int64) (types.Hold, types.Account, error) {
+	return s.resolveHold(ctx, id, types.HoldCaptured)
+}
+
+// ReleaseHold cancels an active hold, returning its amount to the available
+// balance without touching the posted balance.
+func (s *AccountService) ReleaseHold(ctx context.Context, id int64) (types.Hold, types.Account, error) {
+	return s.resolveHold(ctx, id, types.HoldReleased)
+}
+
+func (s *AccountService) resolveHold(ctx context.Context, id int64, status types.HoldStatus) (types.Hold, types.Account, error) {
+	hold, err := s.GetHold(ctx, id)
+	if err != nil {
+		return types.Hold{}, types.Account{}, err
+	}
+
+	unlock := s.lock(hold.AccountID)
+	defer unlock()
+
+	var account types.Account
+	err = retryOnConflict(func() (err error) {
+		// Re-read under the lock: a concurrent capture or release may have
+		// won.
+		hold, err = s.GetHold(ctx, id)
+		if err != nil {
+			return err
+		}
+		if hold.Status != types.HoldActive {
+			return ErrHoldNotActive
+		}
+		account, err = s.Get(ctx, hold.AccountID)
+		if err != nil {
+			return err
+		}
+		// Releasing only returns funds to the available balance, so it
+		// stays possible on a frozen account; capturing moves money.
+		if status == types.HoldCaptured && account.Frozen {
+			return ErrAccountFrozen
+		}
+
+		now := s.clock.Now().UTC()
+		hold.Status, hold.ResolvedAt = status, &now
+		account.Held -= hold.Amount
+		update := balanceUpdate(account)
+		if status == types.HoldCaptured {
+			// Counted again now it is no longer reserved, so a capture
+			// never takes the day's total past the limit, whatever was
+			// reserved when the hold was placed.
+			remaining, err := dailyRemaining(ctx, s.store, account, now)
+			if err != nil {
+				return err
+			}
+			if hold.Amount > remaining {
+				return &DailyLimitError{Limit: account.DailyWithdrawalLimit, Remaining: remaining}
+			}
+			account.Balance -= hold.Amount
+			update = s.ledgerUpdate(account, types.TransactionCapture, hold.Amount)
+			update.Entry.CreatedAt = now // on the day the limit was counted for
+		}
+		if err := s.store.ResolveHold(ctx, hold, update); err != nil {
+			return err
+		}
+		account.Version++
+		return nil
+	})
+	if errors.Is(err, ErrHoldNotActive) {
+		return hold, types.Account{}, err
+	}
+	if err != nil {
+		return types.Hold{}, types.Account{}, err
+	}
+	if status == types.HoldCaptured {
+		s.publish(types.Event{Type: types.EventWithdrawal, AccountID: account.ID, Amount: hold.Amount})
+	}
+	return hold, account, nil
+}
@@ -0,0 +1,110 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"BankSystemGoLang/services"
+	"BankSystemGoLang/store"
+	"BankSystemGoLang/types"
+)
+
+func TestHoldReservesFunds(t *testing.T) {
+	accounts, _, _ := newAccountService(t)
+	account := open(t, accounts, types.CreateAccountRequest{Email: "a@example.com", InitialBalance: 100_00})
+
+	hold, held, err := accounts.PlaceHold(ctx, account.ID, 30_00)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if held.Balance != 100_00 || held.Held != 30_00 {
+		t.Errorf("after hold: balance %s held %s, want 100.00 with 30.00 held", held.Balance, held.Held)
+	}
+
+	if _, err := accounts.Withdraw(ctx, account.ID, 100_00, 0); !errors.Is(err, services.ErrInsufficientFunds) {
+		t.Fatalf("withdrawing the full balance under a hold: err = %v, want ErrInsufficientFunds", err)
+	}
+	if _, _, err := accounts.PlaceHold(ctx, account.ID, 70_01); !errors.Is(err, services.ErrInsufficientFunds) {
+		t.Errorf("second hold beyond the available balance: err = %v, want ErrInsufficientFunds", err)
+	}
+
+	released, _, err := accounts.ReleaseHold(ctx, hold.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if released.Status != types.HoldReleased {
+		t.Errorf("released hold status = %q, want %q", released.Status, types.HoldReleased)
+	}
+	if _, _, err := accounts.ReleaseHold(ctx, hold.ID); !errors.Is(err, services.ErrHoldNotActive) {
+		t.Errorf("releasing twice: err = %v, want ErrHoldNotActive", err)
+	}
+
+	after, err := accounts.Withdraw(ctx, account.ID, 100_00, 0)
+	if err != nil {
+		t.Fatalf("withdraw after release: %v", err)
+	}
+	if after.Balance != 0 || after.Held != 0 {
+		t.Errorf("after withdrawal: balance %s held %s, want both 0.00", after.Balance, after.Held)
+	}
+}
+
+func TestHoldsCountTowardsDailyLimit(t *testing.T) {
+	accounts, _, _ := newAccountService(t)
+	account := open(t, accounts, types.CreateAccountRequest{Email: "a@example.com", InitialBalance: 500_00, DailyWithdrawalLimit: 100_00})
+
+	hold, _, err := accounts.PlaceHold(ctx, account.ID, 60_00)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var limitErr *services.DailyLimitError
+	if _, err := accounts.Withdraw(ctx, account.ID, 50_00, 0); !errors.As(err, &limitErr) || limitErr.Remaining != 40_00 {
+		t.Fatalf("withdrawing past the limit left by a hold: err = %v, want a DailyLimitError with 40.00 remaining", err)
+	}
+	if _, _, err := accounts.PlaceHold(ctx, account.ID, 40_01); !errors.As(err, &limitErr) || limitErr.Remaining != 40_00 {
+		t.Fatalf("second hold past the limit: err = %v, want a DailyLimitError with 40.00 remaining", err)
+	}
+
+	if _, _, err := accounts.CaptureHold(ctx, hold.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := accounts.Withdraw(ctx, account.ID, 40_00, 0); err != nil {
+		t.Fatalf("withdrawing the rest of the limit: %v", err)
+	}
+	if _, err := accounts.Withdraw(ctx, account.ID, 1, 0); !errors.As(err, &limitErr) || limitErr.Remaining != 0 {
+		t.Errorf("withdrawing past a limit used by a capture: err = %v, want a DailyLimitError with nothing remaining", err)
+	}
+}
+
+func TestCaptureChecksDailyLimit(t *testing.T) {
+	accounts, st, clk := newAccountService(t)
+	account := open(t, accounts, types.CreateAccountRequest{Email: "a@example.com", InitialBalance: 500_00, DailyWithdrawalLimit: 100_00})
+	if _, err := accounts.Withdraw(ctx, account.ID, 60_00, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	// A hold written straight to the store skips the check PlaceHold
+	// makes, so only the capture can refuse it.
+	account, err := accounts.Get(ctx, account.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hold := types.Hold{AccountID: account.ID, Amount: 80_00, Status: types.HoldActive, CreatedAt: clk.Now()}
+	update := store.BalanceUpdate{AccountID: account.ID, Balance: account.Balance, Held: 80_00, Version: account.Version}
+	if err := st.CreateHold(ctx, &hold, update); err != nil {
+		t.Fatal(err)
+	}
+
+	var limitErr *services.DailyLimitError
+	if _, _, err := accounts.CaptureHold(ctx, hold.ID); !errors.As(err, &limitErr) || limitErr.Remaining != 40_00 {
+		t.Fatalf("capturing past the limit: err = %v, want a DailyLimitError with 40.00 remaining", err)
+	}
+	if got := balanceOf(t, accounts, account.ID); got != 440_00 {
+		t.Errorf("balance = %s, want 440.00", got)
+	}
+
+	clk.Advance(24 * time.Hour)
+	if _, _, err := accounts.CaptureHold(ctx, hold.ID); err != nil {
+		t.Errorf("capture on the next day: %v", err)
+	}
+}
@@ -0,0 +1,126 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"BankSystemGoLang/clock"
+	"BankSystemGoLang/store"
+	"BankSystemGoLang/types"
+)
+
+var ErrInvalidFrequency = errors.New("frequency must be daily, weekly or monthly")
+
+// ScheduleService stores recurring transfers and executes the due ones
+// through AccountService, so they are locked and recorded in the ledger
+// exactly like transfers made over the API.
+type ScheduleService struct {
+	store    store.Store
+	accounts *AccountService
+	clock    clock.Clock
+}
+
+func NewScheduleService(s store.Store, accounts *AccountService, clk clock.Clock) *ScheduleService {
+	return &ScheduleService{store: s, accounts: accounts, clock: clk}
+}
+
+// ScheduleRun is the outcome of executing one scheduled transfer.
+type ScheduleRun struct {
+	Schedule types.ScheduledTransfer
+	Err      error
+}
+
+// Create validates the request and stores a new schedule owned by userID.
+// The first run is at StartAt, or immediately when it is not given.
+func (s *ScheduleService) Create(ctx context.Context, userID int64, req types.ScheduledTransferRequest) (types.ScheduledTransfer, error) {
+	if req.FromID == req.ToID {
+		return types.ScheduledTransfer{}, ErrSameAccount
+	}
+	if req.Amount <= 0 {
+		return types.ScheduledTransfer{}, ErrInvalidAmount
+	}
+	switch req.Frequency {
+	case types.FrequencyDaily, types.FrequencyWeekly, types.FrequencyMonthly:
+	default:
+		return types.ScheduledTransfer{}, ErrInvalidFrequency
+	}
+	for _, id := range []int64{req.FromID, req.ToID} {
+		account, err := s.accounts.Get(ctx, id)
+		if err != nil {
+			return types.ScheduledTransfer{}, err
+		}
+		if account.Status == types.AccountClosed {
+			return types.ScheduledTransfer{}, ErrAccountClosed
+		}
+	}
+
+	now := s.clock.Now().UTC()
+	start := now
+	if req.StartAt != nil {
+		start = req.StartAt.UTC()
+	}
+
+	st := types.ScheduledTransfer{
+		UserID:    userID,
+		FromID:    req.FromID,
+		ToID:      req.ToID,
+		Amount:    req.Amount,
+		Frequency: req.Frequency,
+		StartAt:   start,
+		NextRunAt: start,
+		CreatedAt: now,
+	}
+	if err := s.store.CreateScheduledTransfer(ctx, &st); err != nil {
+		return types.ScheduledTransfer{}, err
+	}
+	return st, nil
+}
+
+// RunDue executes each schedule that is due once. A successful run moves
+// the schedule to its next occurrence; a schedule that fell several
+// periods behind catches up one period per call. A failed run, such as one
+// hitting insufficient funds, records the error and leaves the schedule
+// due so the next call retries it.
+func (s *ScheduleService) RunDue(ctx context.Context) ([]ScheduleRun, error) {
+	now := s.clock.Now().UTC()
+	due, err := s.store.DueScheduledTransfers(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+
+	runs := make([]ScheduleRun, 0, len(due))
+	for _, st := range due {
+		_, _, _, err := s.accounts.Transfer(ctx, st.FromID, st.ToID, st.Amount)
+		st.LastRunAt = &now
+		if err != nil {
+			st.LastError = err.Error()
+		} else {
+			st.Runs++
+			st.NextRunAt = occurrence(st.StartAt, st.Frequency, st.Runs)
+			st.LastError = ""
+		}
+		if err := s.store.UpdateScheduledTransfer(ctx, st); err != nil {
+			return runs, err
+		}
+		runs = append(runs, ScheduleRun{Schedule: st, Err: err})
+	}
+	return runs, nil
+}
+
+// occurrence returns the n-th run after start. Monthly schedules keep
+// start's day of month, falling back to the month's last day when it is
+// shorter, so a schedule started on the 31st never drifts.
+func occurrence(start time.Time, freq types.Frequency, n int) time.Time {
+	switch freq {
+	case types.FrequencyDaily:
+		return start.AddDate(0, 0, n)
+	case types.FrequencyWeekly:
+		return start.AddDate(0, 0, 7*n)
+	}
+
+	y, m, d := start.Date()
+	lastDay := time.Date(y, m+time.Month(n)+1, 0, 0, 0, 0, 0, start.Location()).Day()
+	return time.Date(y, m+time.Month(n), min(d, lastDay),
+		start.Hour(), start.Minute(), start.Second(), start.Nanosecond(), start.Location())
+}
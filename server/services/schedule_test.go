@@ -0,0 +1,77 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"BankSystemGoLang/services"
+	"BankSystemGoLang/types"
+)
+
+func TestRunDue(t *testing.T) {
+	accounts, st, clk := newAccountService(t)
+	schedules := services.NewScheduleService(st, accounts, clk)
+	from := open(t, accounts, types.CreateAccountRequest{Email: "a@example.com", InitialBalance: 50_00})
+	to := open(t, accounts, types.CreateAccountRequest{Email: "b@example.com"})
+
+	start := testStart.Add(24 * time.Hour)
+	schedule, err := schedules.Create(ctx, 1, types.ScheduledTransferRequest{
+		FromID: from.ID, ToID: to.ID, Amount: 30_00, Frequency: types.FrequencyDaily, StartAt: &start,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	runDue := func() []services.ScheduleRun {
+		t.Helper()
+		runs, err := schedules.RunDue(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return runs
+	}
+
+	if runs := runDue(); len(runs) != 0 {
+		t.Fatalf("ran %d schedules before the start, want none", len(runs))
+	}
+
+	clk.Advance(24 * time.Hour)
+	runs := runDue()
+	if len(runs) != 1 || runs[0].Err != nil {
+		t.Fatalf("runs on the start day = %+v, want one successful run", runs)
+	}
+	if got := runs[0].Schedule; got.Runs != 1 || !got.NextRunAt.Equal(start.Add(24*time.Hour)) {
+		t.Errorf("after the first run: runs %d, next %s; want 1 run, next a day later", got.Runs, got.NextRunAt)
+	}
+	if got := balanceOf(t, accounts, to.ID); got != 30_00 {
+		t.Errorf("destination balance = %s, want 30.00", got)
+	}
+	if runs := runDue(); len(runs) != 0 {
+		t.Errorf("ran again on the same day: %+v", runs)
+	}
+
+	// 20.00 is left, which does not cover the second run.
+	clk.Advance(24 * time.Hour)
+	runs = runDue()
+	if len(runs) != 1 || !errors.Is(runs[0].Err, services.ErrInsufficientFunds) {
+		t.Fatalf("second run = %+v, want it to fail with insufficient funds", runs)
+	}
+	if got := runs[0].Schedule; got.Runs != 1 || !got.NextRunAt.Equal(start.Add(24*time.Hour)) || got.LastError == "" {
+		t.Errorf("after the failed run: runs %d, next %s, error %q; want it still due with the error recorded", got.Runs, got.NextRunAt, got.LastError)
+	}
+	if got := balanceOf(t, accounts, from.ID); got != 20_00 {
+		t.Errorf("source balance = %s, want 20.00", got)
+	}
+
+	if _, err := accounts.Deposit(ctx, from.ID, 10_00, 0); err != nil {
+		t.Fatal(err)
+	}
+	runs = runDue()
+	if len(runs) != 1 || runs[0].Err != nil || runs[0].Schedule.ID != schedule.ID || runs[0].Schedule.LastError != "" {
+		t.Fatalf("retry = %+v, want the schedule to run and clear its error", runs)
+	}
+	if got := balanceOf(t, accounts, from.ID); got != 0 {
+		t.Errorf("source balance = %s, want 0.00", got)
+	}
+}
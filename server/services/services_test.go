@@ -0,0 +1,51 @@
+package services_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"BankSystemGoLang/clock"
+	"BankSystemGoLang/services"
+	"BankSystemGoLang/store"
+	"BankSystemGoLang/types"
+)
+
+var ctx = context.Background()
+
+// testStart is the mock clock's starting time, at noon so a test has
+// room to move within the day.
+var testStart = time.Date(2025, time.March, 10, 12, 0, 0, 0, time.UTC)
+
+// newAccountService returns an AccountService over a MemoryStore at 2%
+// interest, driven by a Mock clock at testStart.
+func newAccountService(t *testing.T) (*services.AccountService, store.Store, *clock.Mock) {
+	t.Helper()
+	st := store.NewMemoryStore()
+	clk := clock.NewMock(testStart)
+	return services.NewAccountService(st, testSecret, big.NewRat(2, 100), services.StaticRates{}, clk), st, clk
+}
+
+// open creates an account for user 1, applying the request's defaults.
+func open(t *testing.T, accounts *services.AccountService, req types.CreateAccountRequest) types.Account {
+	t.Helper()
+	if req.OwnerName == "" {
+		req.OwnerName = "Test Owner"
+	}
+	account, err := accounts.Create(ctx, 1, req)
+	if err != nil {
+		t.Fatalf("create %s: %v", req.Email, err)
+	}
+	return account
+}
+
+// balanceOf returns the account's current balance.
+func balanceOf(t *testing.T, accounts *services.AccountService, id int64) types.Money {
+	t.Helper()
+	account, err := accounts.Get(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return account.Balance
+}
@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"BankSystemGoLang/clock"
+	"BankSystemGoLang/store"
+	"BankSystemGoLang/types"
+)
+
+var (
+	ErrVerificationInvalid = errors.New("verification link is invalid or has already been used")
+	ErrVerificationExpired = errors.New("verification link has expired, request a new one")
+)
+
+// VerificationTTL is how long an emailed verification link stays valid.
+const VerificationTTL = 24 * time.Hour
+
+// Mailer sends email to users. Implementations may call out to an external
+// service.
+type Mailer interface {
+	Send(to, subject, body string) error
+}
+
+// NopMailer discards every message.
+type NopMailer struct{}
+
+func (NopMailer) Send(string, string, string) error { return nil }
+
+// VerificationService emails users a single-use link proving they own
+// their email address, and marks them verified when they follow it.
+type VerificationService struct {
+	store     store.Store
+	mailer    Mailer
+	verifyURL string
+	clock     clock.Clock
+}
+
+// NewVerificationService returns a service sending links to verifyURL, the
+// absolute URL of GET /verify.
+func NewVerificationService(s store.Store, mailer Mailer, verifyURL string, clk clock.Clock) *VerificationService {
+	return &VerificationService{store: s, mailer: mailer, verifyURL: verifyURL, clock: clk}
+}
+
+// Send emails user a link carrying a new token valid for VerificationTTL.
+// Only the token's hash is stored, so the link cannot be rebuilt from the
+// database.
+func (s *VerificationService) Send(ctx context.Context, user types.User) error {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return err
+	}
+	token := hex.EncodeToString(raw)
+
+	expiresAt := s.clock.Now().Add(VerificationTTL).UTC()
+	if err := s.store.CreateVerificationToken(ctx, user.ID, hashToken(token), expiresAt); err != nil {
+		return err
+	}
+
+	link := s.verifyURL + "?token=" + url.QueryEscape(token)
+	body := fmt.Sprintf("Confirm your email address by opening this link within %d hours:\n\n%s\n\n"+
+		"If you did not register, you can ignore this message.\n", int(VerificationTTL.Hours()), link)
+	if err := s.mailer.Send(user.Email, "Verify your email address", body); err != nil {
+		return fmt.Errorf("send verification email: %w", err)
+	}
+	return nil
+}
+
+// Resend sends a new link to the user registered with email. Unknown and
+// already verified addresses are silently skipped so callers cannot learn
+// which addresses are registered.
+func (s *VerificationService) Resend(ctx context.Context, email string) error {
+	user, err := s.store.GetUserByEmail(ctx, email)
+	if errors.Is(err, store.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if user.VerifiedAt != nil {
+		return nil
+	}
+	return s.Send(ctx, user)
+}
+
+// Verify uses up token and marks the user it was issued to verified. An
+// expired token is used up too.
+func (s *VerificationService) Verify(ctx context.Context, token string) (types.User, error) {
+	userID, expiresAt, err := s.store.ConsumeVerificationToken(ctx, hashToken(token))
+	if errors.Is(err, store.ErrNotFound) {
+		return types.User{}, ErrVerificationInvalid
+	}
+	if err != nil {
+		return types.User{}, err
+	}
+	now := s.clock.Now().UTC()
+	if !now.Before(expiresAt) {
+		return types.User{}, ErrVerificationExpired
+	}
+
+	if err := s.store.MarkUserVerified(ctx, userID, now); err != nil {
+		return types.User{}, err
+	}
+	return s.store.GetUser(ctx, userID)
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net"
+	"net/netip"
+	"net/url"
+	"slices"
+
+	"BankSystemGoLang/clock"
+	"BankSystemGoLang/store"
+	"BankSystemGoLang/types"
+)
+
+var (
+	ErrInvalidWebhook = errors.New("webhook needs an absolute http(s) url and at least one of deposit, withdrawal, transfer")
+	ErrWebhookAddress = errors.New("webhook url must resolve only to public addresses")
+)
+
+// AllowWebhookAddress decides which IP addresses webhooks may be created
+// for and delivered to. It is read when a WebhookService or Dispatcher is
+// constructed; tests replace it to deliver to a local server.
+var AllowWebhookAddress = PublicAddress
+
+// PublicAddress reports whether ip is a public unicast address. Loopback,
+// private, link-local and unspecified addresses are refused so a webhook
+// cannot be aimed at the server itself or the network it runs in.
+func PublicAddress(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	return ip.IsGlobalUnicast() && !ip.IsPrivate()
+}
+
+// WebhookService manages webhook subscriptions and decides which of them
+// an event is delivered to.
+type WebhookService struct {
+	store    store.Store
+	accounts *AccountService
+	clock    clock.Clock
+	allow    func(netip.Addr) bool
+}
+
+func NewWebhookService(s store.Store, accounts *AccountService, clk clock.Clock) *WebhookService {
+	return &WebhookService{store: s, accounts: accounts, clock: clk, allow: AllowWebhookAddress}
+}
+
+// Create subscribes the URL to the requested events on userID's accounts
+// and generates the secret its deliveries are signed with. URLs whose host
+// resolves to an address AllowWebhookAddress refuses are rejected with
+// ErrWebhookAddress.
+func (s *WebhookService) Create(ctx context.Context, userID int64, req types.WebhookRequest) (types.Webhook, error) {
+	u, err := url.Parse(req.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || len(req.Events) == 0 {
+		return types.Webhook{}, ErrInvalidWebhook
+	}
+	events := []types.EventType{}
+	for _, e := range req.Events {
+		switch e {
+		case types.EventDeposit, types.EventWithdrawal, types.EventTransfer:
+		default:
+			return types.Webhook{}, ErrInvalidWebhook
+		}
+		if !slices.Contains(events, e) {
+			events = append(events, e)
+		}
+	}
+	if !s.allowedHost(ctx, u.Hostname()) {
+		return types.Webhook{}, ErrWebhookAddress
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return types.Webhook{}, err
+	}
+
+	webhook := types.Webhook{
+		UserID:    userID,
+		URL:       req.URL,
+		Events:    events,
+		Secret:    hex.EncodeToString(secret),
+		CreatedAt: s.clock.Now().UTC(),
+	}
+	if err := s.store.CreateWebhook(ctx, &webhook); err != nil {
+		return types.Webhook{}, err
+	}
+	return webhook, nil
+}
+
+// allowedHost reports whether host resolves, and only to allowed
+// addresses. The dispatcher checks the address again when it connects,
+// since the host may resolve differently by then.
+func (s *WebhookService) allowedHost(ctx context.Context, host string) bool {
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil || len(addrs) == 0 {
+		return false
+	}
+	for _, addr := range addrs {
+		if !s.allow(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// Subscribers returns the webhooks subscribed to event: those of the
+// owners of every account it touches, so both sides of a transfer between
+// users are notified.
+func (s *WebhookService) Subscribers(ctx context.Context, event types.Event) ([]types.Webhook, error) {
+	owners := []int64{}
+	for _, id := range []int64{event.AccountID, event.ToAccountID} {
+		if id == 0 {
+			continue
+		}
+		account, err := s.accounts.Get(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Contains(owners, account.UserID) {
+			owners = append(owners, account.UserID)
+		}
+	}
+
+	var subscribers []types.Webhook
+	for _, owner := range owners {
+		webhooks, err := s.store.ListWebhooks(ctx, owner)
+		if err != nil {
+			return nil, err
+		}
+		for _, w := range webhooks {
+			if slices.Contains(w.Events, event.Type) {
+				subscribers = append(subscribers, w)
+			}
+		}
+	}
+	return subscribers, nil
+}
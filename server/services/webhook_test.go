@@ -0,0 +1,40 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+
+	"BankSystemGoLang/services"
+	"BankSystemGoLang/types"
+)
+
+func TestCreateWebhookAddress(t *testing.T) {
+	accounts, st, clk := newAccountService(t)
+	hooks := services.NewWebhookService(st, accounts, clk)
+
+	tests := []struct {
+		url  string
+		want error
+	}{
+		{"https://93.184.216.34/hook", nil},
+		{"http://127.0.0.1/hook", services.ErrWebhookAddress},
+		{"http://localhost:8080/hook", services.ErrWebhookAddress},
+		{"http://10.0.0.5/hook", services.ErrWebhookAddress},
+		{"http://192.168.1.1/hook", services.ErrWebhookAddress},
+		{"http://169.254.169.254/latest/meta-data", services.ErrWebhookAddress},
+		{"http://0.0.0.0/hook", services.ErrWebhookAddress},
+		{"http://[::1]/hook", services.ErrWebhookAddress},
+		{"http://[fe80::1]/hook", services.ErrWebhookAddress},
+		{"http://[fd00::1]/hook", services.ErrWebhookAddress},
+		{"http://[::ffff:127.0.0.1]/hook", services.ErrWebhookAddress},
+		{"http://no-such-host.invalid/hook", services.ErrWebhookAddress},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			_, err := hooks.Create(ctx, 1, types.WebhookRequest{URL: tt.url, Events: []types.EventType{types.EventDeposit}})
+			if !errors.Is(err, tt.want) {
+				t.Errorf("got = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,483 @@
+package store
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"BankSystemGoLang/types"
+)
+
+// MemoryStore keeps everything in process memory. It is used by tests and
+// for running the server without a database. Nothing it does waits on I/O,
+// so it ignores the contexts it is given.
+type MemoryStore struct {
+	mu           sync.RWMutex
+	accounts     map[int64]types.Account
+	transactions []types.Transaction
+	users        map[int64]types.User
+	schedules    map[int64]types.ScheduledTransfer
+	webhooks     []types.Webhook
+	holds        map[int64]types.Hold
+	audit        []types.AuditEntry
+	verification map[string]verificationToken
+	nextID       int64
+	nextTxID     int64
+	nextUserID   int64
+	nextSchedID  int64
+	nextHookID   int64
+	nextHoldID   int64
+	nextAuditID  int64
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		accounts:     make(map[int64]types.Account),
+		users:        make(map[int64]types.User),
+		schedules:    make(map[int64]types.ScheduledTransfer),
+		holds:        make(map[int64]types.Hold),
+		verification: make(map[string]verificationToken),
+	}
+}
+
+// verificationToken is what CreateVerificationToken stores under a hash.
+type verificationToken struct {
+	userID    int64
+	expiresAt time.Time
+}
+
+func (m *MemoryStore) CreateAccount(_ context.Context, account *types.Account) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, existing := range m.accounts {
+		if strings.EqualFold(existing.Email, account.Email) {
+			return ErrDuplicateEmail
+		}
+	}
+
+	m.nextID++
+	account.ID = m.nextID
+	account.Version = 1
+	m.accounts[account.ID] = *account
+
+	if account.Balance != 0 {
+		m.appendEntry(&types.Transaction{
+			AccountID:    account.ID,
+			Type:         types.TransactionOpening,
+			Amount:       account.Balance,
+			BalanceAfter: account.Balance,
+			CreatedAt:    account.CreatedAt,
+		})
+	}
+	return nil
+}
+
+func (m *MemoryStore) GetAccount(_ context.Context, id int64) (types.Account, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.getAccount(id)
+}
+
+// getAccount is GetAccount for callers already holding m.mu.
+func (m *MemoryStore) getAccount(id int64) (types.Account, error) {
+	account, ok := m.accounts[id]
+	if !ok {
+		return types.Account{}, ErrNotFound
+	}
+	return account, nil
+}
+
+// Atomic holds the store's write lock while fn runs, so nothing else reads
+// or writes until it is done. There is no rollback: an UpdateBalance call
+// fn already made stays applied if fn then fails, so fn should write once,
+// last.
+func (m *MemoryStore) Atomic(_ context.Context, fn func(tx Tx) error) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return fn(memoryTx{m})
+}
+
+// memoryTx is the Tx that Atomic hands to its function; its methods rely
+// on Atomic holding m.mu.
+type memoryTx struct {
+	m *MemoryStore
+}
+
+func (t memoryTx) GetAccount(_ context.Context, id int64) (types.Account, error) {
+	return t.m.getAccount(id)
+}
+
+func (t memoryTx) UpdateBalance(_ context.Context, updates ...BalanceUpdate) error {
+	return t.m.applyUpdates(updates...)
+}
+
+func (t memoryTx) ListTransactionsBetween(_ context.Context, accountID int64, from, to time.Time) ([]types.Transaction, error) {
+	return t.m.listTransactionsBetween(accountID, from, to), nil
+}
+
+func (m *MemoryStore) UpdateBalance(_ context.Context, updates ...BalanceUpdate) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.applyUpdates(updates...)
+}
+
+// applyUpdates is UpdateBalance for callers already holding m.mu. Updates
+// are applied in order to working copies, so several may touch the same
+// account, and only saved once all of them pass their version check.
+func (m *MemoryStore) applyUpdates(updates ...BalanceUpdate) error {
+	updated := make(map[int64]types.Account, len(updates))
+	for _, u := range updates {
+		account, ok := updated[u.AccountID]
+		if !ok {
+			if account, ok = m.accounts[u.AccountID]; !ok {
+				return ErrNotFound
+			}
+		}
+		if account.Version != u.Version {
+			return ErrVersionConflict
+		}
+		account.Balance = u.Balance
+		account.Held = u.Held
+		account.Frozen = u.Frozen
+		account.Version++
+		if u.AccruedAt != nil {
+			account.LastAccruedAt = u.AccruedAt
+		}
+		updated[u.AccountID] = account
+	}
+
+	for id, account := range updated {
+		m.accounts[id] = account
+	}
+	for _, u := range updates {
+		if u.Entry != nil {
+			m.appendEntry(u.Entry)
+		}
+	}
+	return nil
+}
+
+func (m *MemoryStore) ListAccounts(_ context.Context, filter AccountFilter) ([]types.Account, int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	matches := []types.Account{}
+	for _, account := range m.accounts {
+		if filter.UserID != 0 && account.UserID != filter.UserID {
+			continue
+		}
+		if filter.Status != "" && account.Status != filter.Status {
+			continue
+		}
+		matches = append(matches, account)
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].ID < matches[j].ID })
+
+	total := len(matches)
+	start := min(filter.Offset, total)
+	end := total
+	if filter.Limit > 0 {
+		end = min(start+filter.Limit, total)
+	}
+	return matches[start:end], total, nil
+}
+
+func (m *MemoryStore) CloseAccount(_ context.Context, id, version int64, closedAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	account, ok := m.accounts[id]
+	if !ok {
+		return ErrNotFound
+	}
+	if account.Version != version {
+		return ErrVersionConflict
+	}
+	account.Status = types.AccountClosed
+	account.ClosedAt = &closedAt
+	account.Version++
+	m.accounts[id] = account
+	return nil
+}
+
+func (m *MemoryStore) UpdateContact(_ context.Context, id, version int64, ownerName, email string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	account, ok := m.accounts[id]
+	if !ok {
+		return ErrNotFound
+	}
+	if account.Version != version {
+		return ErrVersionConflict
+	}
+	for _, existing := range m.accounts {
+		if existing.ID != id && strings.EqualFold(existing.Email, email) {
+			return ErrDuplicateEmail
+		}
+	}
+	account.OwnerName = ownerName
+	account.Email = email
+	account.Version++
+	m.accounts[id] = account
+	return nil
+}
+
+func (m *MemoryStore) ListTransactions(_ context.Context, filter TransactionFilter) ([]types.Transaction, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	page := []types.Transaction{}
+	skipped := 0
+	for i := len(m.transactions) - 1; i >= 0 && len(page) < filter.Limit; i-- {
+		tx := m.transactions[i]
+		if tx.AccountID != filter.AccountID || (filter.BeforeID != 0 && tx.ID >= filter.BeforeID) {
+			continue
+		}
+		if skipped < filter.Offset {
+			skipped++
+			continue
+		}
+		page = append(page, tx)
+	}
+	return page, nil
+}
+
+func (m *MemoryStore) ListTransactionsBetween(_ context.Context, accountID int64, from, to time.Time) ([]types.Transaction, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.listTransactionsBetween(accountID, from, to), nil
+}
+
+// listTransactionsBetween must be called with m.mu held.
+func (m *MemoryStore) listTransactionsBetween(accountID int64, from, to time.Time) []types.Transaction {
+	entries := []types.Transaction{}
+	for _, tx := range m.transactions {
+		if tx.AccountID == accountID && !tx.CreatedAt.Before(from) && tx.CreatedAt.Before(to) {
+			entries = append(entries, tx)
+		}
+	}
+	return entries
+}
+
+func (m *MemoryStore) CreateHold(_ context.Context, hold *types.Hold, update BalanceUpdate) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.applyUpdates(update); err != nil {
+		return err
+	}
+	m.nextHoldID++
+	hold.ID = m.nextHoldID
+	m.holds[hold.ID] = *hold
+	return nil
+}
+
+func (m *MemoryStore) GetHold(_ context.Context, id int64) (types.Hold, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	hold, ok := m.holds[id]
+	if !ok {
+		return types.Hold{}, ErrNotFound
+	}
+	return hold, nil
+}
+
+func (m *MemoryStore) ResolveHold(_ context.Context, hold types.Hold, update BalanceUpdate) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.holds[hold.ID]; !ok {
+		return ErrNotFound
+	}
+	if err := m.applyUpdates(update); err != nil {
+		return err
+	}
+	m.holds[hold.ID] = hold
+	return nil
+}
+
+func (m *MemoryStore) CreateScheduledTransfer(_ context.Context, st *types.ScheduledTransfer) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.nextSchedID++
+	st.ID = m.nextSchedID
+	m.schedules[st.ID] = *st
+	return nil
+}
+
+func (m *MemoryStore) DueScheduledTransfers(_ context.Context, now time.Time) ([]types.ScheduledTransfer, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	due := []types.ScheduledTransfer{}
+	for _, st := range m.schedules {
+		if !st.NextRunAt.After(now) {
+			due = append(due, st)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		if !due[i].NextRunAt.Equal(due[j].NextRunAt) {
+			return due[i].NextRunAt.Before(due[j].NextRunAt)
+		}
+		return due[i].ID < due[j].ID
+	})
+	return due, nil
+}
+
+func (m *MemoryStore) UpdateScheduledTransfer(_ context.Context, st types.ScheduledTransfer) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	existing, ok := m.schedules[st.ID]
+	if !ok {
+		return ErrNotFound
+	}
+	existing.NextRunAt = st.NextRunAt
+	existing.Runs = st.Runs
+	existing.LastRunAt = st.LastRunAt
+	existing.LastError = st.LastError
+	m.schedules[st.ID] = existing
+	return nil
+}
+
+func (m *MemoryStore) CreateWebhook(_ context.Context, webhook *types.Webhook) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.nextHookID++
+	webhook.ID = m.nextHookID
+	m.webhooks = append(m.webhooks, *webhook)
+	return nil
+}
+
+func (m *MemoryStore) ListWebhooks(_ context.Context, userID int64) ([]types.Webhook, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	webhooks := []types.Webhook{}
+	for _, w := range m.webhooks {
+		if w.UserID == userID {
+			webhooks = append(webhooks, w)
+		}
+	}
+	return webhooks, nil
+}
+
+func (m *MemoryStore) CreateAuditEntry(_ context.Context, entry *types.AuditEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.nextAuditID++
+	entry.ID = m.nextAuditID
+	m.audit = append(m.audit, *entry)
+	return nil
+}
+
+func (m *MemoryStore) ListAuditEntries(_ context.Context, from, to time.Time) ([]types.AuditEntry, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	entries := []types.AuditEntry{}
+	for _, e := range m.audit {
+		if !e.CreatedAt.Before(from) && e.CreatedAt.Before(to) {
+			entries = append(entries, e)
+		}
+	}
+	return entries, nil
+}
+
+func (m *MemoryStore) CreateUser(_ context.Context, user *types.User) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, existing := range m.users {
+		if strings.EqualFold(existing.Email, user.Email) {
+			return ErrDuplicateEmail
+		}
+	}
+
+	m.nextUserID++
+	user.ID = m.nextUserID
+	m.users[user.ID] = *user
+	return nil
+}
+
+func (m *MemoryStore) GetUser(_ context.Context, id int64) (types.User, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	user, ok := m.users[id]
+	if !ok {
+		return types.User{}, ErrNotFound
+	}
+	return user, nil
+}
+
+func (m *MemoryStore) GetUserByEmail(_ context.Context, email string) (types.User, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, user := range m.users {
+		if strings.EqualFold(user.Email, email) {
+			return user, nil
+		}
+	}
+	return types.User{}, ErrNotFound
+}
+
+func (m *MemoryStore) MarkUserVerified(_ context.Context, id int64, at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	user, ok := m.users[id]
+	if !ok {
+		return ErrNotFound
+	}
+	if user.VerifiedAt == nil {
+		user.VerifiedAt = &at
+		m.users[id] = user
+	}
+	return nil
+}
+
+func (m *MemoryStore) CreateVerificationToken(_ context.Context, userID int64, tokenHash string, expiresAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.verification[tokenHash] = verificationToken{userID: userID, expiresAt: expiresAt}
+	return nil
+}
+
+func (m *MemoryStore) ConsumeVerificationToken(_ context.Context, tokenHash string) (int64, time.Time, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	t, ok := m.verification[tokenHash]
+	if !ok {
+		return 0, time.Time{}, ErrNotFound
+	}
+	delete(m.verification, tokenHash)
+	return t.userID, t.expiresAt, nil
+}
+
+// Ping always succeeds: there is no database to lose.
+func (m *MemoryStore) Ping(context.Context) error {
+	return nil
+}
+
+// appendEntry must be called with m.mu held for writing.
+func (m *MemoryStore) appendEntry(entry *types.Transaction) {
+	m.nextTxID++
+	entry.ID = m.nextTxID
+	m.transactions = append(m.transactions, *entry)
+}
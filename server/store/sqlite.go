@@ -0,0 +1,723 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+
+	"BankSystemGoLang/migrations"
+	"BankSystemGoLang/types"
+)
+
+// SQLiteStore is the database/sql backed Store used in production.
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+// OpenSQLite opens (or creates) the SQLite database at dsn and applies the
+// pending schema migrations. Unless dsn says otherwise, every transaction
+// begins with BEGIN IMMEDIATE, taking the database write lock before its
+// first read, and waits up to five seconds for another process's lock.
+func OpenSQLite(dsn string) (*SQLiteStore, error) {
+	db, err := sql.Open("sqlite", withDefaultParams(dsn))
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite: %w", err)
+	}
+	// SQLite allows a single writer at a time; one connection avoids
+	// SQLITE_BUSY errors under concurrent requests.
+	db.SetMaxOpenConns(1)
+
+	if _, err := migrations.Up(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &SQLiteStore{db: db}, nil
+}
+
+// withDefaultParams adds the driver parameters OpenSQLite relies on to dsn,
+// keeping any the caller already set.
+func withDefaultParams(dsn string) string {
+	add := func(param, unlessSet string) {
+		if strings.Contains(dsn, unlessSet) {
+			return
+		}
+		if strings.Contains(dsn, "?") {
+			dsn += "&" + param
+		} else {
+			dsn += "?" + param
+		}
+	}
+	add("_txlock=immediate", "_txlock=")
+	add("_pragma=busy_timeout(5000)", "busy_timeout")
+	return dsn
+}
+
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
+
+func (s *SQLiteStore) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
+func (s *SQLiteStore) CreateAccount(ctx context.Context, account *types.Account) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("create account: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx,
+		`INSERT INTO accounts (user_id, owner_name, email, account_type, currency, balance, overdraft_limit,
+		                       daily_withdrawal_limit, status, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		account.UserID, account.OwnerName, account.Email, account.AccountType, account.Currency, account.Balance,
+		account.OverdraftLimit, account.DailyWithdrawalLimit, account.Status, account.CreatedAt,
+	)
+	if isUniqueViolation(err) {
+		return ErrDuplicateEmail
+	}
+	if err != nil {
+		return fmt.Errorf("create account: %w", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("create account: %w", err)
+	}
+
+	if account.Balance != 0 {
+		err := insertTransaction(ctx, tx, &types.Transaction{
+			AccountID:    id,
+			Type:         types.TransactionOpening,
+			Amount:       account.Balance,
+			BalanceAfter: account.Balance,
+			CreatedAt:    account.CreatedAt,
+		})
+		if err != nil {
+			return fmt.Errorf("create account: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("create account: %w", err)
+	}
+	account.ID = id
+	account.Version = 1
+	return nil
+}
+
+func (s *SQLiteStore) GetAccount(ctx context.Context, id int64) (types.Account, error) {
+	return getAccount(ctx, s.db, id)
+}
+
+func getAccount(ctx context.Context, q querier, id int64) (types.Account, error) {
+	row := q.QueryRowContext(ctx,
+		`SELECT `+accountColumns+` FROM accounts WHERE id = ?`, id,
+	)
+	account, err := scanAccount(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return types.Account{}, ErrNotFound
+	}
+	if err != nil {
+		return types.Account{}, fmt.Errorf("get account: %w", err)
+	}
+	return account, nil
+}
+
+// Atomic runs fn in a transaction, which OpenSQLite makes begin with BEGIN
+// IMMEDIATE: holding the write lock from the start keeps every row fn reads
+// unchanged until fn's writes are committed.
+func (s *SQLiteStore) Atomic(ctx context.Context, fn func(tx Tx) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(sqliteTx{tx}); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
+
+// sqliteTx is the Tx that Atomic hands to its function.
+type sqliteTx struct {
+	tx *sql.Tx
+}
+
+func (t sqliteTx) GetAccount(ctx context.Context, id int64) (types.Account, error) {
+	return getAccount(ctx, t.tx, id)
+}
+
+func (t sqliteTx) UpdateBalance(ctx context.Context, updates ...BalanceUpdate) error {
+	return applyUpdates(ctx, t.tx, updates)
+}
+
+func (t sqliteTx) ListTransactionsBetween(ctx context.Context, accountID int64, from, to time.Time) ([]types.Transaction, error) {
+	return listTransactionsBetween(ctx, t.tx, accountID, from, to)
+}
+
+func (s *SQLiteStore) UpdateBalance(ctx context.Context, updates ...BalanceUpdate) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("update balance: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := applyUpdates(ctx, tx, updates); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("update balance: %w", err)
+	}
+	return nil
+}
+
+func applyUpdates(ctx context.Context, tx *sql.Tx, updates []BalanceUpdate) error {
+	for _, u := range updates {
+		res, err := tx.ExecContext(ctx,
+			`UPDATE accounts
+			    SET balance = ?, held = ?, frozen = ?, last_accrued_at = COALESCE(?, last_accrued_at), version = version + 1
+			  WHERE id = ? AND version = ?`,
+			u.Balance, u.Held, u.Frozen, u.AccruedAt, u.AccountID, u.Version,
+		)
+		if err != nil {
+			return fmt.Errorf("update balance: %w", err)
+		}
+		if n, err := res.RowsAffected(); err != nil {
+			return fmt.Errorf("update balance: %w", err)
+		} else if n == 0 {
+			return missedUpdate(ctx, tx, u.AccountID)
+		}
+		if u.Entry != nil {
+			if err := insertTransaction(ctx, tx, u.Entry); err != nil {
+				return fmt.Errorf("update balance: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// missedUpdate explains why a versioned update of the account matched no
+// row: either it does not exist or another writer changed it first.
+func missedUpdate(ctx context.Context, q querier, id int64) error {
+	var exists bool
+	if err := q.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM accounts WHERE id = ?)`, id).Scan(&exists); err != nil {
+		return fmt.Errorf("update account: %w", err)
+	}
+	if !exists {
+		return ErrNotFound
+	}
+	return ErrVersionConflict
+}
+
+func (s *SQLiteStore) ListAccounts(ctx context.Context, filter AccountFilter) ([]types.Account, int, error) {
+	where, args := "WHERE 1 = 1", []any{}
+	if filter.UserID != 0 {
+		where += " AND user_id = ?"
+		args = append(args, filter.UserID)
+	}
+	if filter.Status != "" {
+		where += " AND status = ?"
+		args = append(args, filter.Status)
+	}
+
+	var total int
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM accounts `+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("list accounts: %w", err)
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = -1 // SQLite: no limit
+	}
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+accountColumns+` FROM accounts `+where+` ORDER BY id LIMIT ? OFFSET ?`,
+		append(args, limit, filter.Offset)...,
+	)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list accounts: %w", err)
+	}
+	defer rows.Close()
+
+	accounts := []types.Account{}
+	for rows.Next() {
+		account, err := scanAccount(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("list accounts: %w", err)
+		}
+		accounts = append(accounts, account)
+	}
+	return accounts, total, rows.Err()
+}
+
+func (s *SQLiteStore) CloseAccount(ctx context.Context, id, version int64, closedAt time.Time) error {
+	res, err := s.db.ExecContext(ctx,
+		`UPDATE accounts SET status = ?, closed_at = ?, version = version + 1 WHERE id = ? AND version = ?`,
+		types.AccountClosed, closedAt, id, version,
+	)
+	if err != nil {
+		return fmt.Errorf("close account: %w", err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("close account: %w", err)
+	} else if n == 0 {
+		return missedUpdate(ctx, s.db, id)
+	}
+	return nil
+}
+
+func (s *SQLiteStore) UpdateContact(ctx context.Context, id, version int64, ownerName, email string) error {
+	res, err := s.db.ExecContext(ctx,
+		`UPDATE accounts SET owner_name = ?, email = ?, version = version + 1 WHERE id = ? AND version = ?`,
+		ownerName, email, id, version,
+	)
+	if isUniqueViolation(err) {
+		return ErrDuplicateEmail
+	}
+	if err != nil {
+		return fmt.Errorf("update contact: %w", err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("update contact: %w", err)
+	} else if n == 0 {
+		return missedUpdate(ctx, s.db, id)
+	}
+	return nil
+}
+
+func (s *SQLiteStore) ListTransactions(ctx context.Context, filter TransactionFilter) ([]types.Transaction, error) {
+	where, args := "WHERE account_id = ?", []any{filter.AccountID}
+	if filter.BeforeID != 0 {
+		where += " AND id < ?"
+		args = append(args, filter.BeforeID)
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+transactionColumns+` FROM transactions `+where+` ORDER BY id DESC LIMIT ? OFFSET ?`,
+		append(args, filter.Limit, filter.Offset)...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list transactions: %w", err)
+	}
+	return scanTransactions(rows)
+}
+
+func (s *SQLiteStore) ListTransactionsBetween(ctx context.Context, accountID int64, from, to time.Time) ([]types.Transaction, error) {
+	return listTransactionsBetween(ctx, s.db, accountID, from, to)
+}
+
+func listTransactionsBetween(ctx context.Context, q querier, accountID int64, from, to time.Time) ([]types.Transaction, error) {
+	rows, err := q.QueryContext(ctx,
+		`SELECT `+transactionColumns+`
+		   FROM transactions
+		  WHERE account_id = ? AND created_at >= ? AND created_at < ?
+		  ORDER BY id`,
+		accountID, from.UTC(), to.UTC(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list transactions: %w", err)
+	}
+	return scanTransactions(rows)
+}
+
+func (s *SQLiteStore) CreateHold(ctx context.Context, hold *types.Hold, update BalanceUpdate) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("create hold: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx,
+		`INSERT INTO holds (account_id, amount, status, created_at) VALUES (?, ?, ?, ?)`,
+		hold.AccountID, hold.Amount, hold.Status, hold.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("create hold: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("create hold: %w", err)
+	}
+	if err := applyUpdates(ctx, tx, []BalanceUpdate{update}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("create hold: %w", err)
+	}
+	hold.ID = id
+	return nil
+}
+
+func (s *SQLiteStore) GetHold(ctx context.Context, id int64) (types.Hold, error) {
+	var (
+		h          types.Hold
+		resolvedAt sql.NullTime
+	)
+	err := s.db.QueryRowContext(ctx,
+		`SELECT id, account_id, amount, status, created_at, resolved_at FROM holds WHERE id = ?`, id,
+	).Scan(&h.ID, &h.AccountID, &h.Amount, &h.Status, &h.CreatedAt, &resolvedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return types.Hold{}, ErrNotFound
+	}
+	if err != nil {
+		return types.Hold{}, fmt.Errorf("get hold: %w", err)
+	}
+	if resolvedAt.Valid {
+		h.ResolvedAt = &resolvedAt.Time
+	}
+	return h, nil
+}
+
+func (s *SQLiteStore) ResolveHold(ctx context.Context, hold types.Hold, update BalanceUpdate) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("resolve hold: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `UPDATE holds SET status = ?, resolved_at = ? WHERE id = ?`, hold.Status, hold.ResolvedAt, hold.ID)
+	if err != nil {
+		return fmt.Errorf("resolve hold: %w", err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("resolve hold: %w", err)
+	} else if n == 0 {
+		return ErrNotFound
+	}
+	if err := applyUpdates(ctx, tx, []BalanceUpdate{update}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("resolve hold: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLiteStore) CreateScheduledTransfer(ctx context.Context, st *types.ScheduledTransfer) error {
+	res, err := s.db.ExecContext(ctx,
+		`INSERT INTO scheduled_transfers (user_id, from_id, to_id, amount, frequency, start_at, next_run_at, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		st.UserID, st.FromID, st.ToID, st.Amount, st.Frequency, st.StartAt.UTC(), st.NextRunAt.UTC(), st.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("create scheduled transfer: %w", err)
+	}
+
+	st.ID, err = res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("create scheduled transfer: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLiteStore) DueScheduledTransfers(ctx context.Context, now time.Time) ([]types.ScheduledTransfer, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, user_id, from_id, to_id, amount, frequency, start_at, next_run_at, runs, last_run_at, last_error, created_at
+		   FROM scheduled_transfers
+		  WHERE next_run_at <= ?
+		  ORDER BY next_run_at, id`,
+		now.UTC(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("due scheduled transfers: %w", err)
+	}
+	defer rows.Close()
+
+	due := []types.ScheduledTransfer{}
+	for rows.Next() {
+		var (
+			st        types.ScheduledTransfer
+			lastRunAt sql.NullTime
+		)
+		err := rows.Scan(&st.ID, &st.UserID, &st.FromID, &st.ToID, &st.Amount, &st.Frequency,
+			&st.StartAt, &st.NextRunAt, &st.Runs, &lastRunAt, &st.LastError, &st.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("due scheduled transfers: %w", err)
+		}
+		if lastRunAt.Valid {
+			st.LastRunAt = &lastRunAt.Time
+		}
+		due = append(due, st)
+	}
+	return due, rows.Err()
+}
+
+func (s *SQLiteStore) UpdateScheduledTransfer(ctx context.Context, st types.ScheduledTransfer) error {
+	res, err := s.db.ExecContext(ctx,
+		`UPDATE scheduled_transfers SET next_run_at = ?, runs = ?, last_run_at = ?, last_error = ? WHERE id = ?`,
+		st.NextRunAt.UTC(), st.Runs, st.LastRunAt, st.LastError, st.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("update scheduled transfer: %w", err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("update scheduled transfer: %w", err)
+	} else if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *SQLiteStore) CreateWebhook(ctx context.Context, webhook *types.Webhook) error {
+	events := make([]string, len(webhook.Events))
+	for i, e := range webhook.Events {
+		events[i] = string(e)
+	}
+
+	res, err := s.db.ExecContext(ctx,
+		`INSERT INTO webhooks (user_id, url, events, secret, created_at) VALUES (?, ?, ?, ?, ?)`,
+		webhook.UserID, webhook.URL, strings.Join(events, ","), webhook.Secret, webhook.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("create webhook: %w", err)
+	}
+
+	webhook.ID, err = res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("create webhook: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLiteStore) ListWebhooks(ctx context.Context, userID int64) ([]types.Webhook, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, user_id, url, events, secret, created_at FROM webhooks WHERE user_id = ? ORDER BY id`, userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	webhooks := []types.Webhook{}
+	for rows.Next() {
+		var (
+			w      types.Webhook
+			events string
+		)
+		if err := rows.Scan(&w.ID, &w.UserID, &w.URL, &events, &w.Secret, &w.CreatedAt); err != nil {
+			return nil, fmt.Errorf("list webhooks: %w", err)
+		}
+		for _, e := range strings.Split(events, ",") {
+			w.Events = append(w.Events, types.EventType(e))
+		}
+		webhooks = append(webhooks, w)
+	}
+	return webhooks, rows.Err()
+}
+
+func (s *SQLiteStore) CreateAuditEntry(ctx context.Context, entry *types.AuditEntry) error {
+	res, err := s.db.ExecContext(ctx,
+		`INSERT INTO audit_log (admin_id, action, account_id, request, created_at) VALUES (?, ?, ?, ?, ?)`,
+		entry.AdminID, entry.Action, entry.AccountID, string(entry.Request), entry.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("create audit entry: %w", err)
+	}
+
+	entry.ID, err = res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("create audit entry: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLiteStore) ListAuditEntries(ctx context.Context, from, to time.Time) ([]types.AuditEntry, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, admin_id, action, account_id, request, created_at FROM audit_log
+		 WHERE created_at >= ? AND created_at < ? ORDER BY id`, from, to,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list audit entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []types.AuditEntry{}
+	for rows.Next() {
+		var (
+			e       types.AuditEntry
+			request string
+		)
+		if err := rows.Scan(&e.ID, &e.AdminID, &e.Action, &e.AccountID, &request, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("list audit entries: %w", err)
+		}
+		if request != "" {
+			e.Request = json.RawMessage(request)
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+func (s *SQLiteStore) CreateUser(ctx context.Context, user *types.User) error {
+	res, err := s.db.ExecContext(ctx,
+		`INSERT INTO users (email, password_hash, role, created_at, verified_at) VALUES (?, ?, ?, ?, ?)`,
+		user.Email, user.PasswordHash, user.Role, user.CreatedAt, user.VerifiedAt,
+	)
+	if isUniqueViolation(err) {
+		return ErrDuplicateEmail
+	}
+	if err != nil {
+		return fmt.Errorf("create user: %w", err)
+	}
+
+	user.ID, err = res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("create user: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLiteStore) GetUser(ctx context.Context, id int64) (types.User, error) {
+	return scanUser(s.db.QueryRowContext(ctx, `SELECT `+userColumns+` FROM users WHERE id = ?`, id))
+}
+
+func (s *SQLiteStore) GetUserByEmail(ctx context.Context, email string) (types.User, error) {
+	return scanUser(s.db.QueryRowContext(ctx, `SELECT `+userColumns+` FROM users WHERE email = ?`, email))
+}
+
+func (s *SQLiteStore) MarkUserVerified(ctx context.Context, id int64, at time.Time) error {
+	res, err := s.db.ExecContext(ctx, `UPDATE users SET verified_at = COALESCE(verified_at, ?) WHERE id = ?`, at, id)
+	if err != nil {
+		return fmt.Errorf("mark user verified: %w", err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("mark user verified: %w", err)
+	} else if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *SQLiteStore) CreateVerificationToken(ctx context.Context, userID int64, tokenHash string, expiresAt time.Time) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO email_verifications (token_hash, user_id, expires_at) VALUES (?, ?, ?)`,
+		tokenHash, userID, expiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("create verification token: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLiteStore) ConsumeVerificationToken(ctx context.Context, tokenHash string) (int64, time.Time, error) {
+	var (
+		userID    int64
+		expiresAt time.Time
+	)
+	err := s.db.QueryRowContext(ctx,
+		`DELETE FROM email_verifications WHERE token_hash = ? RETURNING user_id, expires_at`, tokenHash,
+	).Scan(&userID, &expiresAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, time.Time{}, ErrNotFound
+	}
+	if err != nil {
+		return 0, time.Time{}, fmt.Errorf("consume verification token: %w", err)
+	}
+	return userID, expiresAt, nil
+}
+
+const userColumns = `id, email, password_hash, role, created_at, verified_at`
+
+func scanUser(row scanner) (types.User, error) {
+	var (
+		u          types.User
+		verifiedAt sql.NullTime
+	)
+	err := row.Scan(&u.ID, &u.Email, &u.PasswordHash, &u.Role, &u.CreatedAt, &verifiedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return types.User{}, ErrNotFound
+	}
+	if err != nil {
+		return types.User{}, fmt.Errorf("get user: %w", err)
+	}
+	if verifiedAt.Valid {
+		u.VerifiedAt = &verifiedAt.Time
+	}
+	return u, nil
+}
+
+func insertTransaction(ctx context.Context, tx *sql.Tx, t *types.Transaction) error {
+	res, err := tx.ExecContext(ctx,
+		`INSERT INTO transactions (account_id, type, amount, balance_after, exchange_rate, converted_amount, reason, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		t.AccountID, t.Type, t.Amount, t.BalanceAfter, t.ExchangeRate, t.ConvertedAmount, t.Reason, t.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	t.ID, err = res.LastInsertId()
+	return err
+}
+
+// querier is what *sql.DB and *sql.Tx have in common for reads, so a read
+// can run either on its own or inside a transaction.
+type querier interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+const accountColumns = `id, user_id, owner_name, email, account_type, currency, balance, held, overdraft_limit,
+	daily_withdrawal_limit, status, frozen, created_at, closed_at, last_accrued_at, version`
+
+func scanAccount(row scanner) (types.Account, error) {
+	var (
+		a                   types.Account
+		closedAt, accruedAt sql.NullTime
+	)
+	err := row.Scan(&a.ID, &a.UserID, &a.OwnerName, &a.Email, &a.AccountType, &a.Currency, &a.Balance, &a.Held, &a.OverdraftLimit,
+		&a.DailyWithdrawalLimit, &a.Status, &a.Frozen, &a.CreatedAt, &closedAt, &accruedAt, &a.Version)
+	if closedAt.Valid {
+		a.ClosedAt = &closedAt.Time
+	}
+	if accruedAt.Valid {
+		a.LastAccruedAt = &accruedAt.Time
+	}
+	return a, err
+}
+
+const transactionColumns = `id, account_id, type, amount, balance_after, exchange_rate, converted_amount, reason, created_at`
+
+func scanTransactions(rows *sql.Rows) ([]types.Transaction, error) {
+	defer rows.Close()
+
+	transactions := []types.Transaction{}
+	for rows.Next() {
+		var (
+			t         types.Transaction
+			converted sql.NullInt64
+		)
+		err := rows.Scan(&t.ID, &t.AccountID, &t.Type, &t.Amount, &t.BalanceAfter, &t.ExchangeRate, &converted, &t.Reason, &t.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("list transactions: %w", err)
+		}
+		if converted.Valid {
+			amount := types.Money(converted.Int64)
+			t.ConvertedAmount = &amount
+		}
+		transactions = append(transactions, t)
+	}
+	return transactions, rows.Err()
+}
+
+func isUniqueViolation(err error) bool {
+	var sqliteErr *sqlite.Error
+	return errors.As(err, &sqliteErr) && sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE
+}
@@ -0,0 +1,88 @@
+package store_test
+
+import (
+	"errors"
+	"math/big"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"BankSystemGoLang/clock"
+	"BankSystemGoLang/services"
+	"BankSystemGoLang/store"
+	"BankSystemGoLang/types"
+)
+
+// TestSQLiteConcurrentTransfers runs reciprocal transfers through two
+// stores opened on the same file, as two server processes would be. Nothing
+// in process serialises them, so only the database's locking and the
+// stores' version checks keep the balances and the ledger consistent.
+func TestSQLiteConcurrentTransfers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bank.db")
+	first := openSQLiteAt(t, path)
+	second := openSQLiteAt(t, path)
+	a := createAccount(t, first, "a@example.com", 100_00)
+	b := createAccount(t, first, "b@example.com", 100_00)
+
+	instances := []*services.AccountService{
+		services.NewAccountService(first, "test-secret", big.NewRat(0, 1), services.StaticRates{}, clock.Real{}),
+		services.NewAccountService(second, "test-secret", big.NewRat(0, 1), services.StaticRates{}, clock.Real{}),
+	}
+
+	var (
+		wg        sync.WaitGroup
+		succeeded atomic.Int64
+	)
+	for i := range 100 {
+		accounts := instances[i%2]
+		from, to, amount := a.ID, b.ID, types.Money(7_00)
+		if i%4 >= 2 {
+			from, to, amount = b.ID, a.ID, 3_00
+		}
+		wg.Go(func() {
+			_, _, _, err := accounts.Transfer(ctx, from, to, amount)
+			switch {
+			case err == nil:
+				succeeded.Add(1)
+			case !errors.Is(err, services.ErrInsufficientFunds):
+				t.Error(err)
+			}
+		})
+	}
+	wg.Wait()
+
+	var total types.Money
+	entries := 0
+	for _, id := range []int64{a.ID, b.ID} {
+		account, err := first.GetAccount(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		total += account.Balance
+
+		ledger, err := first.ListTransactions(ctx, store.TransactionFilter{AccountID: id, Limit: 1000})
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries += len(ledger)
+		var sum types.Money
+		for _, entry := range ledger {
+			switch entry.Type {
+			case types.TransactionOpening, types.TransactionTransferIn:
+				sum += entry.Amount
+			case types.TransactionTransferOut:
+				sum -= entry.Amount
+			}
+		}
+		if sum != account.Balance || ledger[0].BalanceAfter != account.Balance {
+			t.Errorf("account %d: balance %s, ledger sums to %s with latest balance_after %s", id, account.Balance, sum, ledger[0].BalanceAfter)
+		}
+	}
+	if total != 200_00 {
+		t.Errorf("total balance = %s, want 200.00", total)
+	}
+	if want := 2 + 2*int(succeeded.Load()); entries != want {
+		t.Errorf("ledger has %d entries, want %d for %d transfers", entries, want, succeeded.Load())
+	}
+}
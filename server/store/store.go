@@ -0,0 +1,136 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"BankSystemGoLang/types"
+)
+
+var (
+	ErrNotFound        = errors.New("record not found")
+	ErrDuplicateEmail  = errors.New("email already in use")
+	ErrVersionConflict = errors.New("account was changed by another writer")
+)
+
+// BalanceUpdate sets the posted balance, held total and frozen flag of a
+// single account and records the ledger entry explaining the change, if any. It only
+// applies while the account is still at Version, the version it was read
+// at, and fails with ErrVersionConflict otherwise; applying it increments
+// the version.
+type BalanceUpdate struct {
+	AccountID int64
+	Balance   types.Money
+	Held      types.Money
+	Frozen    bool
+	Version   int64
+	Entry     *types.Transaction
+	// AccruedAt, when set, becomes the account's LastAccruedAt.
+	AccruedAt *time.Time
+}
+
+// AccountFilter selects a page of accounts. Zero values match everything.
+type AccountFilter struct {
+	UserID int64
+	Status types.AccountStatus
+	Limit  int
+	Offset int
+}
+
+// TransactionFilter selects a page of an account's ledger, newest first.
+// A non-zero BeforeID keeps only entries older than that one, which is how
+// cursors resume a listing without the drift of a growing offset.
+type TransactionFilter struct {
+	AccountID int64
+	BeforeID  int64
+	Limit     int
+	Offset    int
+}
+
+// Tx is the part of a Store available to a function run by Atomic.
+type Tx interface {
+	GetAccount(ctx context.Context, id int64) (types.Account, error)
+	UpdateBalance(ctx context.Context, updates ...BalanceUpdate) error
+	ListTransactionsBetween(ctx context.Context, accountID int64, from, to time.Time) ([]types.Transaction, error)
+}
+
+// Store persists accounts and their ledger. Implementations must be safe
+// for concurrent use. Every method stops waiting on the database and
+// returns the context's error once ctx is done.
+type Store interface {
+	// Atomic runs fn as a single transaction: no other writer can change
+	// the accounts fn reads through tx before fn returns, and fn's writes
+	// are all committed together, or none of them when fn fails. fn must
+	// only use tx, not the Store, while it runs.
+	Atomic(ctx context.Context, fn func(tx Tx) error) error
+	// CreateAccount inserts the account and fills in its generated ID and
+	// initial version. A non-zero opening balance is recorded as an opening
+	// ledger entry.
+	CreateAccount(ctx context.Context, account *types.Account) error
+	GetAccount(ctx context.Context, id int64) (types.Account, error)
+	// UpdateBalance applies every update and appends its ledger entry, or
+	// does none of them.
+	UpdateBalance(ctx context.Context, updates ...BalanceUpdate) error
+	// ListAccounts returns the requested page of matching accounts ordered
+	// by ID, together with the total number of matches.
+	ListAccounts(ctx context.Context, filter AccountFilter) ([]types.Account, int, error)
+	// CloseAccount marks the account closed; the row and its ledger stay.
+	// Like a BalanceUpdate it is conditional on version and increments it.
+	CloseAccount(ctx context.Context, id, version int64, closedAt time.Time) error
+	// UpdateContact saves the account's owner name and email, failing with
+	// ErrDuplicateEmail when another account uses the email. It is
+	// conditional on version and increments it.
+	UpdateContact(ctx context.Context, id, version int64, ownerName, email string) error
+	// ListTransactions returns an account's ledger, newest first.
+	ListTransactions(ctx context.Context, filter TransactionFilter) ([]types.Transaction, error)
+	// ListTransactionsBetween returns the entries created in [from, to),
+	// oldest first.
+	ListTransactionsBetween(ctx context.Context, accountID int64, from, to time.Time) ([]types.Transaction, error)
+
+	// CreateHold inserts the hold, filling in its ID, and applies update
+	// reserving its amount, or does neither.
+	CreateHold(ctx context.Context, hold *types.Hold, update BalanceUpdate) error
+	GetHold(ctx context.Context, id int64) (types.Hold, error)
+	// ResolveHold saves the hold's new Status and ResolvedAt and applies
+	// update, or does neither.
+	ResolveHold(ctx context.Context, hold types.Hold, update BalanceUpdate) error
+
+	// CreateScheduledTransfer inserts the schedule and fills in its ID.
+	CreateScheduledTransfer(ctx context.Context, st *types.ScheduledTransfer) error
+	// DueScheduledTransfers returns the schedules whose next run is at or
+	// before now, earliest first.
+	DueScheduledTransfers(ctx context.Context, now time.Time) ([]types.ScheduledTransfer, error)
+	// UpdateScheduledTransfer saves the run bookkeeping (NextRunAt, Runs,
+	// LastRunAt, LastError) of an existing schedule.
+	UpdateScheduledTransfer(ctx context.Context, st types.ScheduledTransfer) error
+
+	// CreateWebhook inserts the webhook and fills in its generated ID.
+	CreateWebhook(ctx context.Context, webhook *types.Webhook) error
+	// ListWebhooks returns the user's webhooks ordered by ID.
+	ListWebhooks(ctx context.Context, userID int64) ([]types.Webhook, error)
+
+	// CreateAuditEntry appends the entry to the audit log and fills in its
+	// generated ID. Entries are never changed or deleted.
+	CreateAuditEntry(ctx context.Context, entry *types.AuditEntry) error
+	// ListAuditEntries returns the entries created in [from, to), oldest
+	// first.
+	ListAuditEntries(ctx context.Context, from, to time.Time) ([]types.AuditEntry, error)
+
+	// CreateUser inserts the user and fills in its generated ID.
+	CreateUser(ctx context.Context, user *types.User) error
+	GetUser(ctx context.Context, id int64) (types.User, error)
+	GetUserByEmail(ctx context.Context, email string) (types.User, error)
+	// MarkUserVerified records that the user's email was verified at the
+	// given time. A user already verified keeps the earlier time.
+	MarkUserVerified(ctx context.Context, id int64, at time.Time) error
+	// CreateVerificationToken stores the hash of a token verifying the
+	// user's email until expiresAt.
+	CreateVerificationToken(ctx context.Context, userID int64, tokenHash string, expiresAt time.Time) error
+	// ConsumeVerificationToken deletes the token with the given hash, so it
+	// cannot be used again, and returns what it was stored with.
+	ConsumeVerificationToken(ctx context.Context, tokenHash string) (userID int64, expiresAt time.Time, err error)
+
+	// Ping checks that the backing database is reachable.
+	Ping(ctx context.Context) error
+}
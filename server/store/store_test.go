@@ -0,0 +1,254 @@
+package store_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"BankSystemGoLang/store"
+	"BankSystemGoLang/types"
+)
+
+var ctx = context.Background()
+
+var testTime = time.Date(2025, time.March, 10, 12, 0, 0, 0, time.UTC)
+
+// eachStore runs test as a subtest against a fresh MemoryStore and a fresh
+// SQLiteStore in a temporary file, so both implementations are held to the
+// same behaviour.
+func eachStore(t *testing.T, test func(t *testing.T, s store.Store)) {
+	t.Run("memory", func(t *testing.T) {
+		test(t, store.NewMemoryStore())
+	})
+	t.Run("sqlite", func(t *testing.T) {
+		test(t, openSQLite(t))
+	})
+}
+
+func openSQLite(t *testing.T) *store.SQLiteStore {
+	t.Helper()
+	return openSQLiteAt(t, filepath.Join(t.TempDir(), "bank.db"))
+}
+
+// openSQLiteAt opens a SQLiteStore on the file at path, closed when the
+// test ends.
+func openSQLiteAt(t *testing.T, path string) *store.SQLiteStore {
+	t.Helper()
+	s, err := store.OpenSQLite(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func createAccount(t *testing.T, s store.Store, email string, balance types.Money) types.Account {
+	t.Helper()
+	account := types.Account{
+		UserID:               1,
+		OwnerName:            "Test Owner",
+		Email:                email,
+		AccountType:          types.AccountChecking,
+		Currency:             "USD",
+		Balance:              balance,
+		DailyWithdrawalLimit: 5000_00,
+		Status:               types.AccountOpen,
+		CreatedAt:            testTime,
+	}
+	if err := s.CreateAccount(ctx, &account); err != nil {
+		t.Fatalf("create %s: %v", email, err)
+	}
+	return account
+}
+
+func TestCreateAndGetAccount(t *testing.T) {
+	eachStore(t, func(t *testing.T, s store.Store) {
+		created := createAccount(t, s, "a@example.com", 10_00)
+		if created.ID <= 0 || created.Version != 1 {
+			t.Fatalf("created ID %d version %d, want a positive ID at version 1", created.ID, created.Version)
+		}
+
+		got, err := s.GetAccount(ctx, created.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Email != "a@example.com" || got.Balance != 10_00 || !got.CreatedAt.Equal(testTime) {
+			t.Errorf("got %+v, want the account as created", got)
+		}
+
+		entries, err := s.ListTransactions(ctx, store.TransactionFilter{AccountID: created.ID, Limit: 10})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 1 || entries[0].Type != types.TransactionOpening || entries[0].Amount != 10_00 {
+			t.Errorf("ledger = %+v, want one opening entry of 10.00", entries)
+		}
+
+		if _, err := s.GetAccount(ctx, created.ID+1); !errors.Is(err, store.ErrNotFound) {
+			t.Errorf("GetAccount of a missing ID: err = %v, want ErrNotFound", err)
+		}
+	})
+}
+
+func TestCreateAccountDuplicateEmail(t *testing.T) {
+	eachStore(t, func(t *testing.T, s store.Store) {
+		createAccount(t, s, "a@example.com", 0)
+		dup := types.Account{UserID: 2, OwnerName: "Other", Email: "a@example.com", Status: types.AccountOpen}
+		if err := s.CreateAccount(ctx, &dup); !errors.Is(err, store.ErrDuplicateEmail) {
+			t.Errorf("err = %v, want ErrDuplicateEmail", err)
+		}
+	})
+}
+
+func TestUpdateBalanceVersionConflict(t *testing.T) {
+	eachStore(t, func(t *testing.T, s store.Store) {
+		a := createAccount(t, s, "a@example.com", 10_00)
+		b := createAccount(t, s, "b@example.com", 10_00)
+
+		// b's update is stale, so a's must not be applied either.
+		err := s.UpdateBalance(ctx,
+			store.BalanceUpdate{AccountID: a.ID, Balance: 5_00, Version: a.Version},
+			store.BalanceUpdate{AccountID: b.ID, Balance: 15_00, Version: b.Version + 1},
+		)
+		if !errors.Is(err, store.ErrVersionConflict) {
+			t.Fatalf("err = %v, want ErrVersionConflict", err)
+		}
+		if got, _ := s.GetAccount(ctx, a.ID); got.Balance != 10_00 || got.Version != a.Version {
+			t.Errorf("a = %s at version %d, want it untouched", got.Balance, got.Version)
+		}
+
+		entry := &types.Transaction{AccountID: a.ID, Type: types.TransactionWithdrawal, Amount: 5_00, BalanceAfter: 5_00, CreatedAt: testTime}
+		if err := s.UpdateBalance(ctx, store.BalanceUpdate{AccountID: a.ID, Balance: 5_00, Version: a.Version, Entry: entry}); err != nil {
+			t.Fatal(err)
+		}
+		if got, _ := s.GetAccount(ctx, a.ID); got.Balance != 5_00 || got.Version != a.Version+1 {
+			t.Errorf("a = %s at version %d, want 5.00 at version %d", got.Balance, got.Version, a.Version+1)
+		}
+	})
+}
+
+func TestListAccounts(t *testing.T) {
+	eachStore(t, func(t *testing.T, s store.Store) {
+		for _, email := range []string{"a@example.com", "b@example.com", "c@example.com"} {
+			createAccount(t, s, email, 0)
+		}
+		other := types.Account{UserID: 2, OwnerName: "Other", Email: "d@example.com", Status: types.AccountOpen, CreatedAt: testTime}
+		if err := s.CreateAccount(ctx, &other); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.CloseAccount(ctx, 1, 1, testTime); err != nil {
+			t.Fatal(err)
+		}
+
+		page, total, err := s.ListAccounts(ctx, store.AccountFilter{UserID: 1, Limit: 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if total != 3 || len(page) != 2 || page[0].ID != 1 || page[1].ID != 2 {
+			t.Errorf("user 1: %d of %d accounts, want the first 2 of 3 by ID", len(page), total)
+		}
+
+		page, total, err = s.ListAccounts(ctx, store.AccountFilter{Status: types.AccountOpen, Limit: 10, Offset: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if total != 3 || len(page) != 2 || page[0].ID != 3 {
+			t.Errorf("open accounts from offset 1: %d of %d starting at %v, want 2 of 3 starting at 3", len(page), total, page)
+		}
+	})
+}
+
+func TestListTransactions(t *testing.T) {
+	eachStore(t, func(t *testing.T, s store.Store) {
+		account := createAccount(t, s, "a@example.com", 1_00)
+		balance := account.Balance
+		for i := range 4 {
+			balance += 1_00
+			entry := &types.Transaction{
+				AccountID:    account.ID,
+				Type:         types.TransactionDeposit,
+				Amount:       1_00,
+				BalanceAfter: balance,
+				CreatedAt:    testTime.Add(time.Duration(i+1) * time.Hour),
+			}
+			update := store.BalanceUpdate{AccountID: account.ID, Balance: balance, Version: account.Version + int64(i), Entry: entry}
+			if err := s.UpdateBalance(ctx, update); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		newest, err := s.ListTransactions(ctx, store.TransactionFilter{AccountID: account.ID, Limit: 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(newest) != 2 || newest[0].BalanceAfter != 5_00 || newest[1].BalanceAfter != 4_00 {
+			t.Fatalf("first page = %+v, want the two newest entries", newest)
+		}
+		older, err := s.ListTransactions(ctx, store.TransactionFilter{AccountID: account.ID, BeforeID: newest[1].ID, Limit: 10})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(older) != 3 || older[0].BalanceAfter != 3_00 || older[2].Type != types.TransactionOpening {
+			t.Errorf("entries before %d = %+v, want the three older ones", newest[1].ID, older)
+		}
+
+		between, err := s.ListTransactionsBetween(ctx, account.ID, testTime.Add(time.Hour), testTime.Add(3*time.Hour))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(between) != 2 || between[0].BalanceAfter != 2_00 || between[1].BalanceAfter != 3_00 {
+			t.Errorf("entries in [1h, 3h) = %+v, want the first two deposits oldest first", between)
+		}
+	})
+}
+
+func TestAtomicRollsBack(t *testing.T) {
+	eachStore(t, func(t *testing.T, s store.Store) {
+		account := createAccount(t, s, "a@example.com", 10_00)
+		failure := errors.New("fail after reading")
+		err := s.Atomic(ctx, func(tx store.Tx) error {
+			if _, err := tx.GetAccount(ctx, account.ID); err != nil {
+				return err
+			}
+			return failure
+		})
+		if !errors.Is(err, failure) {
+			t.Errorf("err = %v, want fn's error", err)
+		}
+		if got, _ := s.GetAccount(ctx, account.ID); got.Version != account.Version {
+			t.Errorf("version = %d, want %d", got.Version, account.Version)
+		}
+	})
+}
+
+func TestUsers(t *testing.T) {
+	eachStore(t, func(t *testing.T, s store.Store) {
+		user := types.User{Email: "a@example.com", PasswordHash: "hash", Role: types.RoleUser, CreatedAt: testTime}
+		if err := s.CreateUser(ctx, &user); err != nil {
+			t.Fatal(err)
+		}
+		dup := types.User{Email: "a@example.com", PasswordHash: "hash", Role: types.RoleUser, CreatedAt: testTime}
+		if err := s.CreateUser(ctx, &dup); !errors.Is(err, store.ErrDuplicateEmail) {
+			t.Errorf("duplicate user: err = %v, want ErrDuplicateEmail", err)
+		}
+
+		if err := s.MarkUserVerified(ctx, user.ID, testTime); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.MarkUserVerified(ctx, user.ID, testTime.Add(time.Hour)); err != nil {
+			t.Fatal(err)
+		}
+		got, err := s.GetUserByEmail(ctx, "a@example.com")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.ID != user.ID || got.VerifiedAt == nil || !got.VerifiedAt.Equal(testTime) {
+			t.Errorf("got %+v, want user %d verified at the first time", got, user.ID)
+		}
+		if _, err := s.GetUserByEmail(ctx, "b@example.com"); !errors.Is(err, store.ErrNotFound) {
+			t.Errorf("unknown email: err = %v, want ErrNotFound", err)
+		}
+	})
+}
@@ -0,0 +1,167 @@
+package types
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+	"time"
+)
+
+// AccountStatus tells whether an account can still move money.
+type AccountStatus string
+
+const (
+	AccountOpen   AccountStatus = "open"
+	AccountClosed AccountStatus = "closed"
+)
+
+// AccountType decides which features an account supports; only savings
+// accounts earn interest.
+type AccountType string
+
+const (
+	AccountChecking AccountType = "checking"
+	AccountSavings  AccountType = "savings"
+)
+
+// Account is a single bank account owned by a customer. Closed accounts are
+// kept so their history stays readable. Balance is the posted balance and
+// Held the total of its active holds; withdrawals may take the available
+// balance, Balance minus Held, down to -OverdraftLimit, and withdraw at
+// most DailyWithdrawalLimit per UTC day. A Frozen account
+// can still be read but moves no money until it is unfrozen. Version
+// increases with every change to the account and is sent as its ETag.
+type Account struct {
+	ID                   int64         `json:"id"`
+	UserID               int64         `json:"user_id"`
+	OwnerName            string        `json:"owner_name"`
+	Email                string        `json:"email"`
+	AccountType          AccountType   `json:"account_type"`
+	Currency             string        `json:"currency"`
+	Balance              Money         `json:"balance"`
+	Held                 Money         `json:"held"`
+	OverdraftLimit       Money         `json:"overdraft_limit"`
+	DailyWithdrawalLimit Money         `json:"daily_withdrawal_limit"`
+	Status               AccountStatus `json:"status"`
+	Frozen               bool          `json:"frozen"`
+	CreatedAt            time.Time     `json:"created_at"`
+	ClosedAt             *time.Time    `json:"closed_at,omitempty"`
+	LastAccruedAt        *time.Time    `json:"last_accrued_at,omitempty"`
+	Version              int64         `json:"version"`
+}
+
+// Available is the part of the balance not reserved by active holds.
+func (a Account) Available() Money {
+	return a.Balance - a.Held
+}
+
+// MarshalJSON adds the derived available balance to the account's fields.
+func (a Account) MarshalJSON() ([]byte, error) {
+	type account Account // drops the method so json.Marshal doesn't recurse
+	return json.Marshal(struct {
+		account
+		Available Money `json:"available"`
+	}{account(a), a.Available()})
+}
+
+// CreateAccountRequest is the body accepted by POST /accounts.
+type CreateAccountRequest struct {
+	OwnerName      string      `json:"owner_name" validate:"required"`
+	Email          string      `json:"email" validate:"required,email"`
+	InitialBalance Money       `json:"initial_balance" validate:"gte=0"`
+	AccountType    AccountType `json:"account_type" validate:"omitempty,oneof=checking savings"`
+	Currency       string      `json:"currency" validate:"omitempty,iso4217"`
+	OverdraftLimit Money       `json:"overdraft_limit" validate:"gte=0"`
+	// DailyWithdrawalLimit defaults to services.DefaultDailyWithdrawalLimit.
+	DailyWithdrawalLimit Money `json:"daily_withdrawal_limit" validate:"gte=0"`
+}
+
+// UpdateAccountRequest is the body accepted by PATCH /accounts/:id. Only
+// the fields present are changed.
+type UpdateAccountRequest struct {
+	OwnerName *string `json:"owner_name,omitempty"`
+	Email     *string `json:"email,omitempty" validate:"omitempty,email"`
+}
+
+// ImmutableFieldError is returned when an UpdateAccountRequest body sets a
+// field that cannot be changed, such as balance or id.
+type ImmutableFieldError struct {
+	Field string
+}
+
+func (e *ImmutableFieldError) Error() string {
+	return e.Field + " cannot be changed; only owner_name and email can be updated"
+}
+
+// UnmarshalJSON rejects any field other than owner_name and email with an
+// *ImmutableFieldError, naming the first in alphabetical order.
+func (r *UpdateAccountRequest) UnmarshalJSON(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	for _, name := range slices.Sorted(maps.Keys(fields)) {
+		if name != "owner_name" && name != "email" {
+			return &ImmutableFieldError{Field: name}
+		}
+	}
+	type request UpdateAccountRequest // drops the method so json.Unmarshal doesn't recurse
+	return json.Unmarshal(data, (*request)(r))
+}
+
+// AccountPage is the response of GET /accounts.
+type AccountPage struct {
+	Accounts []Account `json:"accounts"`
+	Total    int       `json:"total"`
+	Limit    int       `json:"limit"`
+	Offset   int       `json:"offset"`
+}
+
+// FreezeRequest is the body accepted by the freeze and unfreeze endpoints.
+// Reason is recorded in the account's ledger.
+type FreezeRequest struct {
+	Reason string `json:"reason" validate:"required,max=500"`
+}
+
+// AmountRequest is the body accepted by the deposit and withdraw endpoints.
+type AmountRequest struct {
+	Amount Money `json:"amount"`
+}
+
+// BalanceResponse reports an account balance after a money movement.
+type BalanceResponse struct {
+	AccountID int64 `json:"account_id"`
+	Balance   Money `json:"balance"`
+	Version   int64 `json:"version"`
+}
+
+// TransferRequest is the body accepted by POST /transfers.
+type TransferRequest struct {
+	FromID int64 `json:"from_id" validate:"required,gt=0"`
+	ToID   int64 `json:"to_id" validate:"required,gt=0"`
+	Amount Money `json:"amount" validate:"gt=0"`
+}
+
+// TransferResponse reports both balances after a completed transfer, or
+// the balances it would leave when DryRun is set. ToBalance is only set
+// when the caller owns the destination account too. Amount is in the
+// source account's currency; when the destination uses another one,
+// ConvertedAmount is what it was credited at ExchangeRate.
+type TransferResponse struct {
+	FromID          int64  `json:"from_id"`
+	ToID            int64  `json:"to_id"`
+	Amount          Money  `json:"amount"`
+	ConvertedAmount *Money `json:"converted_amount,omitempty"`
+	ExchangeRate    string `json:"exchange_rate,omitempty"`
+	FromBalance     Money  `json:"from_balance"`
+	ToBalance       *Money `json:"to_balance,omitempty"`
+	DryRun          bool   `json:"dry_run,omitempty"`
+}
+
+// InterestResponse reports the outcome of POST /accounts/:id/accrue-interest.
+type InterestResponse struct {
+	AccountID int64 `json:"account_id"`
+	Days      int   `json:"days"`
+	Interest  Money `json:"interest"`
+	Balance   Money `json:"balance"`
+}
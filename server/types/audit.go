@@ -0,0 +1,35 @@
+package types
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AuditAction names an admin action recorded in the audit log.
+type AuditAction string
+
+const (
+	AuditFreeze         AuditAction = "freeze"
+	AuditUnfreeze       AuditAction = "unfreeze"
+	AuditAccrueInterest AuditAction = "accrue_interest"
+	AuditCloseAccount   AuditAction = "close_account"
+)
+
+// AuditEntry records that the admin AdminID applied Action to AccountID.
+// Request is the JSON body the admin sent, if any.
+type AuditEntry struct {
+	ID        int64           `json:"id"`
+	AdminID   int64           `json:"admin_id"`
+	Action    AuditAction     `json:"action"`
+	AccountID int64           `json:"account_id"`
+	Request   json.RawMessage `json:"request,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// AuditLog is the response of GET /audit-log, oldest entry first. From and
+// To are both inclusive calendar days (UTC).
+type AuditLog struct {
+	Entries []AuditEntry `json:"entries"`
+	From    time.Time    `json:"from"`
+	To      time.Time    `json:"to"`
+}
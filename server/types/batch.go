@@ -0,0 +1,35 @@
+package types
+
+// BatchItemStatus reports what happened to one transfer of a batch.
+type BatchItemStatus string
+
+const (
+	BatchItemApplied    BatchItemStatus = "applied"
+	BatchItemRolledBack BatchItemStatus = "rolled_back" // would have applied, but a later item failed
+	BatchItemFailed     BatchItemStatus = "failed"
+	BatchItemSkipped    BatchItemStatus = "skipped" // not attempted after an earlier item failed
+)
+
+// BatchTransferRequest is the body accepted by POST /transfers/batch.
+type BatchTransferRequest struct {
+	Transfers []TransferRequest `json:"transfers" validate:"required,min=1,max=100,dive"`
+}
+
+// BatchTransferResult reports one transfer of a batch. Code and Error are
+// only set on the item that failed the batch.
+type BatchTransferResult struct {
+	Index           int             `json:"index"`
+	FromID          int64           `json:"from_id"`
+	ToID            int64           `json:"to_id"`
+	Amount          Money           `json:"amount"`
+	ConvertedAmount *Money          `json:"converted_amount,omitempty"`
+	ExchangeRate    string          `json:"exchange_rate,omitempty"`
+	Status          BatchItemStatus `json:"status"`
+	Code            string          `json:"code,omitempty"`
+	Error           string          `json:"error,omitempty"`
+}
+
+// BatchTransferResponse is the response of a batch that was applied.
+type BatchTransferResponse struct {
+	Results []BatchTransferResult `json:"results"`
+}
@@ -0,0 +1,81 @@
+package types
+
+// Machine-readable error codes returned in ErrorResponse.Code.
+const (
+	CodeBadRequest             = "BAD_REQUEST"
+	CodeValidationFailed       = "VALIDATION_FAILED"
+	CodeInvalidAccountID       = "INVALID_ACCOUNT_ID"
+	CodeInvalidAmount          = "INVALID_AMOUNT"
+	CodeInvalidEmail           = "INVALID_EMAIL"
+	CodeInvalidPagination      = "INVALID_PAGINATION"
+	CodeInvalidCursor          = "INVALID_CURSOR"
+	CodeInvalidStatus          = "INVALID_STATUS"
+	CodeInvalidDate            = "INVALID_DATE"
+	CodeInvalidDateRange       = "INVALID_DATE_RANGE"
+	CodeInvalidFormat          = "INVALID_FORMAT"
+	CodeInvalidFrequency       = "INVALID_FREQUENCY"
+	CodeInvalidWebhook         = "INVALID_WEBHOOK"
+	CodeOwnerNameRequired      = "OWNER_NAME_REQUIRED"
+	CodeNegativeBalance        = "NEGATIVE_BALANCE"
+	CodeSameAccount            = "SAME_ACCOUNT"
+	CodePasswordTooShort       = "PASSWORD_TOO_SHORT"
+	CodeUnauthorized           = "UNAUTHORIZED"
+	CodeInvalidCredentials     = "INVALID_CREDENTIALS"
+	CodeTokenExpired           = "TOKEN_EXPIRED"
+	CodeInvalidToken           = "INVALID_TOKEN"
+	CodeEmailNotVerified       = "EMAIL_NOT_VERIFIED"
+	CodeVerificationInvalid    = "VERIFICATION_INVALID"
+	CodeVerificationExpired    = "VERIFICATION_EXPIRED"
+	CodeForbidden              = "FORBIDDEN"
+	CodeNotFound               = "NOT_FOUND"
+	CodeAccountNotFound        = "ACCOUNT_NOT_FOUND"
+	CodeMethodNotAllowed       = "METHOD_NOT_ALLOWED"
+	CodeDuplicateEmail         = "DUPLICATE_EMAIL"
+	CodeUserExists             = "USER_EXISTS"
+	CodeInsufficientFunds      = "INSUFFICIENT_FUNDS"
+	CodeDailyLimitExceeded     = "DAILY_LIMIT_EXCEEDED"
+	CodeAccountClosed          = "ACCOUNT_CLOSED"
+	CodeAccountFrozen          = "ACCOUNT_FROZEN"
+	CodeAlreadyFrozen          = "ACCOUNT_ALREADY_FROZEN"
+	CodeNotFrozen              = "ACCOUNT_NOT_FROZEN"
+	CodeBalanceNotZero         = "BALANCE_NOT_ZERO"
+	CodeActiveHolds            = "ACTIVE_HOLDS"
+	CodeBatchTooLarge          = "BATCH_TOO_LARGE"
+	CodeBatchFailed            = "BATCH_FAILED"
+	CodeInvalidIfMatch         = "INVALID_IF_MATCH"
+	CodeVersionMismatch        = "VERSION_MISMATCH"
+	CodeConcurrentUpdate       = "CONCURRENT_UPDATE"
+	CodeInvalidHoldID          = "INVALID_HOLD_ID"
+	CodeHoldNotFound           = "HOLD_NOT_FOUND"
+	CodeHoldNotActive          = "HOLD_NOT_ACTIVE"
+	CodeNotSavingsAccount      = "NOT_SAVINGS_ACCOUNT"
+	CodeInvalidOverdraft       = "INVALID_OVERDRAFT_LIMIT"
+	CodeInvalidDailyLimit      = "INVALID_DAILY_WITHDRAWAL_LIMIT"
+	CodeImmutableField         = "IMMUTABLE_FIELD"
+	CodeExchangeRate           = "EXCHANGE_RATE_UNAVAILABLE"
+	CodeIdempotencyKeyReused   = "IDEMPOTENCY_KEY_REUSED"
+	CodeIdempotencyKeyInFlight = "IDEMPOTENCY_KEY_IN_FLIGHT"
+	CodeRateLimited            = "RATE_LIMITED"
+	CodeTimeout                = "TIMEOUT"
+	CodeInternal               = "INTERNAL_ERROR"
+)
+
+// ErrorResponse is the JSON body of every failed request. Remaining is only
+// set when a withdrawal exceeds the daily limit, reporting what may still
+// be withdrawn today, and Results for a failed batch, reporting each of its
+// items.
+type ErrorResponse struct {
+	Code      string                `json:"code"`
+	Error     string                `json:"error"`
+	RequestID string                `json:"request_id,omitempty"`
+	Balance   *Money                `json:"balance,omitempty"`
+	Remaining *Money                `json:"remaining,omitempty"`
+	Fields    []FieldError          `json:"fields,omitempty"`
+	Results   []BatchTransferResult `json:"results,omitempty"`
+}
+
+// FieldError describes one invalid field of a request body.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
@@ -0,0 +1,23 @@
+package types
+
+import "time"
+
+// EventType names a kind of committed money movement.
+type EventType string
+
+const (
+	EventDeposit    EventType = "deposit"
+	EventWithdrawal EventType = "withdrawal"
+	EventTransfer   EventType = "transfer"
+)
+
+// Event describes a money movement after it has been committed to the
+// ledger. ToAccountID is only set for transfers, where AccountID is the
+// source account.
+type Event struct {
+	Type        EventType `json:"type"`
+	AccountID   int64     `json:"account_id"`
+	ToAccountID int64     `json:"to_account_id,omitempty"`
+	Amount      Money     `json:"amount"`
+	OccurredAt  time.Time `json:"occurred_at"`
+}
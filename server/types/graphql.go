@@ -0,0 +1,8 @@
+package types
+
+// GraphQLRequest is the body accepted by POST /graphql.
+type GraphQLRequest struct {
+	Query         string         `json:"query" validate:"required"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+}
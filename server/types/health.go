@@ -0,0 +1,13 @@
+package types
+
+const (
+	HealthOK        = "ok"
+	HealthUnhealthy = "unhealthy"
+)
+
+// HealthResponse is the body of GET /health. DB is only set when the
+// database check fails.
+type HealthResponse struct {
+	Status string `json:"status"`
+	DB     string `json:"db,omitempty"`
+}
@@ -0,0 +1,29 @@
+package types
+
+import "time"
+
+// HoldStatus tracks a hold from placement until it is captured or released.
+type HoldStatus string
+
+const (
+	HoldActive   HoldStatus = "active"
+	HoldCaptured HoldStatus = "captured"
+	HoldReleased HoldStatus = "released"
+)
+
+// Hold reserves Amount of an account's available balance for a pending
+// card-style payment. The posted balance only changes if the hold is
+// captured; releasing it returns the amount to the available balance.
+type Hold struct {
+	ID         int64      `json:"id"`
+	AccountID  int64      `json:"account_id"`
+	Amount     Money      `json:"amount"`
+	Status     HoldStatus `json:"status"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+}
+
+// HoldRequest is the body accepted by POST /accounts/:id/holds.
+type HoldRequest struct {
+	Amount Money `json:"amount" validate:"gt=0"`
+}
@@ -0,0 +1,83 @@
+package types_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"BankSystemGoLang/types"
+)
+
+func TestManySmallDepositsAreExact(t *testing.T) {
+	var balance types.Money
+	for range 10_000 {
+		var deposit types.Money
+		if err := json.Unmarshal([]byte(`0.01`), &deposit); err != nil {
+			t.Fatal(err)
+		}
+		balance += deposit
+	}
+	if got := balance.String(); got != "100.00" {
+		t.Errorf("balance = %s, want 100.00", got)
+	}
+}
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		in   string
+		want types.Money
+		err  bool
+	}{
+		{in: "100", want: 100_00},
+		{in: "100.5", want: 100_50},
+		{in: "100.50", want: 100_50},
+		{in: "0.01", want: 1},
+		{in: "-3.25", want: -3_25},
+		{in: "+7.10", want: 7_10},
+		{in: " 12.00 ", want: 12_00},
+		{in: "1.", want: 1_00},
+		{in: "92233720368547757.99", want: 9223372036854775799},
+		{in: "92233720368547758.00", err: true},
+		{in: "1.234", err: true},
+		{in: ".5", err: true},
+		{in: "", err: true},
+		{in: "-", err: true},
+		{in: "1e2", err: true},
+		{in: "1,50", err: true},
+		{in: "--1", err: true},
+		{in: "abc", err: true},
+	}
+	for _, tt := range tests {
+		got, err := types.ParseMoney(tt.in)
+		if tt.err {
+			if !errors.Is(err, types.ErrInvalidMoney) {
+				t.Errorf("ParseMoney(%q) = %s, %v; want ErrInvalidMoney", tt.in, got, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParseMoney(%q) = %d, %v; want %d", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestMoneyJSON(t *testing.T) {
+	out, err := json.Marshal(types.Money(-1_05))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != `"-1.05"` {
+		t.Errorf("Marshal(-1.05) = %s, want \"-1.05\"", out)
+	}
+
+	for _, in := range []string{`"19.99"`, `19.99`} {
+		var m types.Money
+		if err := json.Unmarshal([]byte(in), &m); err != nil || m != 19_99 {
+			t.Errorf("Unmarshal(%s) = %d, %v; want 1999", in, m, err)
+		}
+	}
+	var m types.Money
+	if err := json.Unmarshal([]byte(`19.999`), &m); err == nil {
+		t.Errorf("Unmarshal(19.999) = %d, want an error", m)
+	}
+}
@@ -0,0 +1,40 @@
+package types
+
+import "time"
+
+// Frequency is how often a scheduled transfer repeats.
+type Frequency string
+
+const (
+	FrequencyDaily   Frequency = "daily"
+	FrequencyWeekly  Frequency = "weekly"
+	FrequencyMonthly Frequency = "monthly"
+)
+
+// ScheduledTransfer moves Amount from FromID to ToID every period starting
+// at StartAt. Runs counts the successful executions; a failed run keeps
+// NextRunAt so it is retried, and records why in LastError.
+type ScheduledTransfer struct {
+	ID        int64      `json:"id"`
+	UserID    int64      `json:"user_id"`
+	FromID    int64      `json:"from_id"`
+	ToID      int64      `json:"to_id"`
+	Amount    Money      `json:"amount"`
+	Frequency Frequency  `json:"frequency"`
+	StartAt   time.Time  `json:"start_at"`
+	NextRunAt time.Time  `json:"next_run_at"`
+	Runs      int        `json:"runs"`
+	LastRunAt *time.Time `json:"last_run_at,omitempty"`
+	LastError string     `json:"last_error,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// ScheduledTransferRequest is the body accepted by POST
+// /scheduled-transfers. StartAt defaults to now.
+type ScheduledTransferRequest struct {
+	FromID    int64      `json:"from_id" validate:"required,gt=0"`
+	ToID      int64      `json:"to_id" validate:"required,gt=0"`
+	Amount    Money      `json:"amount" validate:"gt=0"`
+	Frequency Frequency  `json:"frequency" validate:"required,oneof=daily weekly monthly"`
+	StartAt   *time.Time `json:"start_at"`
+}
@@ -0,0 +1,54 @@
+package types
+
+import "time"
+
+// TransactionType identifies the kind of money movement in the ledger.
+type TransactionType string
+
+const (
+	TransactionOpening     TransactionType = "opening"
+	TransactionDeposit     TransactionType = "deposit"
+	TransactionWithdrawal  TransactionType = "withdrawal"
+	TransactionTransferIn  TransactionType = "transfer_in"
+	TransactionTransferOut TransactionType = "transfer_out"
+	TransactionInterest    TransactionType = "interest"
+	TransactionCapture     TransactionType = "capture"
+	TransactionFreeze      TransactionType = "freeze"
+	TransactionUnfreeze    TransactionType = "unfreeze"
+)
+
+// Transaction is a single ledger entry. Amount is always positive and in
+// the account's currency; Type tells whether it was credited or debited.
+// Both legs of a cross-currency transfer record the ExchangeRate used, and
+// the debit leg also records the ConvertedAmount credited to the
+// destination. Freeze and unfreeze entries move no money: their Amount is
+// zero and Reason records why staff acted.
+type Transaction struct {
+	ID              int64           `json:"id"`
+	AccountID       int64           `json:"account_id"`
+	Type            TransactionType `json:"type"`
+	Amount          Money           `json:"amount"`
+	BalanceAfter    Money           `json:"balance_after"`
+	ExchangeRate    string          `json:"exchange_rate,omitempty"`
+	ConvertedAmount *Money          `json:"converted_amount,omitempty"`
+	Reason          string          `json:"reason,omitempty"`
+	CreatedAt       time.Time       `json:"created_at"`
+}
+
+// TransactionPage is the response of GET /accounts/:id/transactions.
+// NextCursor, when set, is passed back as ?cursor= to fetch the next page.
+type TransactionPage struct {
+	Transactions []Transaction `json:"transactions"`
+	Limit        int           `json:"limit"`
+	Offset       int           `json:"offset"`
+	NextCursor   string        `json:"next_cursor,omitempty"`
+}
+
+// Statement is an account's activity over a period, oldest entry first.
+// From and To are both inclusive calendar days (UTC).
+type Statement struct {
+	Account      Account
+	From         time.Time
+	To           time.Time
+	Transactions []Transaction
+}
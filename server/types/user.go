@@ -0,0 +1,46 @@
+package types
+
+import "time"
+
+// Role controls which endpoints a user may call.
+type Role string
+
+const (
+	RoleUser  Role = "user"
+	RoleAdmin Role = "admin"
+)
+
+// User is someone who can log in and own accounts. PasswordHash holds the
+// bcrypt hash and is never serialised. VerifiedAt is unset until the user
+// has followed the link emailed at registration.
+type User struct {
+	ID           int64      `json:"id"`
+	Email        string     `json:"email"`
+	PasswordHash string     `json:"-"`
+	Role         Role       `json:"role"`
+	CreatedAt    time.Time  `json:"created_at"`
+	VerifiedAt   *time.Time `json:"verified_at,omitempty"`
+}
+
+// RegisterRequest is the body accepted by POST /register.
+type RegisterRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// LoginRequest is the body accepted by POST /login.
+type LoginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// ResendVerificationRequest is the body accepted by POST /verify/resend.
+type ResendVerificationRequest struct {
+	Email string `json:"email"`
+}
+
+// LoginResponse carries the signed access token.
+type LoginResponse struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
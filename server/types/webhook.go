@@ -0,0 +1,21 @@
+package types
+
+import "time"
+
+// Webhook subscribes URL to the listed event types on the owner's
+// accounts. Deliveries are signed with Secret, which is only returned when
+// the webhook is created.
+type Webhook struct {
+	ID        int64       `json:"id"`
+	UserID    int64       `json:"user_id"`
+	URL       string      `json:"url"`
+	Events    []EventType `json:"events"`
+	Secret    string      `json:"secret,omitempty"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+// WebhookRequest is the body accepted by POST /webhooks.
+type WebhookRequest struct {
+	URL    string      `json:"url" validate:"required,http_url"`
+	Events []EventType `json:"events" validate:"required,min=1,dive,oneof=deposit withdrawal transfer"`
+}
@@ -0,0 +1,189 @@
+// Package webhooks delivers account events to subscribed URLs in the
+// background.
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"sync"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+
+	"BankSystemGoLang/services"
+	"BankSystemGoLang/types"
+)
+
+const (
+	// HeaderSignature carries "sha256=" followed by the hex HMAC-SHA256 of
+	// the request body, keyed with the webhook's secret.
+	HeaderSignature = "X-Signature"
+	// HeaderEvent names the event type of a delivery.
+	HeaderEvent = "X-Webhook-Event"
+
+	queueSize = 1024
+	workers   = 4
+)
+
+// Backoff is the wait before each retry of a failed delivery; a delivery is
+// attempted len(Backoff)+1 times in total.
+var Backoff = []time.Duration{time.Second, 5 * time.Second, 30 * time.Second}
+
+// Dispatcher queues events and POSTs them to their subscribers from a
+// small pool of workers, so a slow or failing endpoint never delays the
+// request that produced the event.
+type Dispatcher struct {
+	webhooks *services.WebhookService
+	client   *http.Client
+	log      *zap.Logger
+	queue    chan types.Event
+	backoff  []time.Duration
+}
+
+func NewDispatcher(webhooks *services.WebhookService, log *zap.Logger) *Dispatcher {
+	return &Dispatcher{
+		webhooks: webhooks,
+		client:   newClient(services.AllowWebhookAddress),
+		log:      log,
+		queue:    make(chan types.Event, queueSize),
+		backoff:  Backoff,
+	}
+}
+
+// newClient returns the HTTP client deliveries are sent with. Every address
+// it connects to, including those of redirects, is checked with allow after
+// DNS resolution, so a host that re-resolves to an internal address after
+// the webhook was created is still refused. Proxies are not used, as the
+// check would then apply to the proxy rather than the endpoint.
+func newClient(allow func(netip.Addr) bool) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			addr, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return err
+			}
+			if !allow(addr.Addr()) {
+				return fmt.Errorf("webhook address %s is not public", addr.Addr())
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{Timeout: 10 * time.Second, Transport: transport}
+}
+
+// Publish queues event for delivery without blocking. It is meant to be
+// passed to AccountService.Subscribe. When the queue is full the event is
+// dropped and logged.
+func (d *Dispatcher) Publish(event types.Event) {
+	select {
+	case d.queue <- event:
+	default:
+		d.log.Warn("webhook queue full, dropping event",
+			zap.String("type", string(event.Type)), zap.Int64("account_id", event.AccountID))
+	}
+}
+
+// Run delivers queued events until ctx is cancelled. Pending retries are
+// abandoned on shutdown.
+func (d *Dispatcher) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Go(func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case event := <-d.queue:
+					d.dispatch(ctx, event)
+				}
+			}
+		})
+	}
+	wg.Wait()
+}
+
+func (d *Dispatcher) dispatch(ctx context.Context, event types.Event) {
+	subscribers, err := d.webhooks.Subscribers(ctx, event)
+	if err != nil {
+		d.log.Error("find webhook subscribers", zap.Error(err))
+		return
+	}
+	if len(subscribers) == 0 {
+		return
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		d.log.Error("encode webhook event", zap.Error(err))
+		return
+	}
+	for _, w := range subscribers {
+		d.deliver(ctx, w, event.Type, body)
+	}
+}
+
+// deliver POSTs body to the webhook, retrying with backoff until it
+// answers 2xx, the attempts run out or ctx is cancelled.
+func (d *Dispatcher) deliver(ctx context.Context, w types.Webhook, eventType types.EventType, body []byte) {
+	signature := Sign(w.Secret, body)
+	for attempt := 0; ; attempt++ {
+		err := d.post(ctx, w.URL, eventType, signature, body)
+		if err == nil {
+			return
+		}
+
+		fields := []zap.Field{zap.Int64("webhook_id", w.ID), zap.Int("attempt", attempt+1), zap.Error(err)}
+		if attempt >= len(d.backoff) {
+			d.log.Error("webhook delivery failed, giving up", fields...)
+			return
+		}
+		d.log.Warn("webhook delivery failed, retrying", fields...)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(d.backoff[attempt]):
+		}
+	}
+}
+
+func (d *Dispatcher) post(ctx context.Context, url string, eventType types.EventType, signature string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, string(eventType))
+	req.Header.Set(HeaderSignature, signature)
+
+	res, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("endpoint answered %s", res.Status)
+	}
+	return nil
+}
+
+// Sign returns the X-Signature value for body: receivers recompute it with
+// their secret and compare using hmac.Equal.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
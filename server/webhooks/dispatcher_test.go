@@ -0,0 +1,156 @@
+package webhooks_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"BankSystemGoLang/clock"
+	"BankSystemGoLang/services"
+	"BankSystemGoLang/store"
+	"BankSystemGoLang/types"
+	"BankSystemGoLang/webhooks"
+)
+
+type delivery struct {
+	header http.Header
+	body   []byte
+}
+
+// allowLoopback lets webhooks be created for and delivered to httptest
+// servers by services and dispatchers constructed until it is undone.
+func allowLoopback(t *testing.T) (undo func()) {
+	allow := services.AllowWebhookAddress
+	services.AllowWebhookAddress = func(ip netip.Addr) bool { return ip.IsLoopback() || allow(ip) }
+	undo = func() { services.AllowWebhookAddress = allow }
+	t.Cleanup(undo)
+	return undo
+}
+
+func TestDeliverySignature(t *testing.T) {
+	allowLoopback(t)
+	received := make(chan delivery, 1)
+	endpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- delivery{header: r.Header.Clone(), body: body}
+	}))
+	defer endpoint.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	st := store.NewMemoryStore()
+	clk := clock.NewMock(time.Date(2025, time.March, 10, 12, 0, 0, 0, time.UTC))
+	accounts := services.NewAccountService(st, "test-secret", big.NewRat(0, 1), services.StaticRates{}, clk)
+	hooks := services.NewWebhookService(st, accounts, clk)
+	dispatcher := webhooks.NewDispatcher(hooks, zap.NewNop())
+	accounts.Subscribe(dispatcher.Publish)
+	go dispatcher.Run(ctx)
+
+	account, err := accounts.Create(ctx, 1, types.CreateAccountRequest{OwnerName: "Alice", Email: "alice@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hook, err := hooks.Create(ctx, 1, types.WebhookRequest{URL: endpoint.URL, Events: []types.EventType{types.EventDeposit}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := accounts.Deposit(ctx, account.ID, 12_34, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	var got delivery
+	select {
+	case got = <-received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no delivery within 5s")
+	}
+
+	// Check the signature the way a receiver would.
+	mac := hmac.New(sha256.New, []byte(hook.Secret))
+	mac.Write(got.body)
+	want := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	if sig := got.header.Get(webhooks.HeaderSignature); !hmac.Equal([]byte(sig), []byte(want)) {
+		t.Errorf("%s = %q, want %q", webhooks.HeaderSignature, sig, want)
+	}
+	if event := got.header.Get(webhooks.HeaderEvent); event != string(types.EventDeposit) {
+		t.Errorf("%s = %q, want %q", webhooks.HeaderEvent, event, types.EventDeposit)
+	}
+	var event types.Event
+	if err := json.Unmarshal(got.body, &event); err != nil {
+		t.Fatal(err)
+	}
+	if event.Type != types.EventDeposit || event.AccountID != account.ID || event.Amount != 12_34 {
+		t.Errorf("event = %+v, want a deposit of 12.34 into account %d", event, account.ID)
+	}
+}
+
+func TestDeliveryRechecksAddressAtDialTime(t *testing.T) {
+	requests := make(chan struct{}, 1)
+	endpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests <- struct{}{}
+	}))
+	defer endpoint.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	st := store.NewMemoryStore()
+	clk := clock.NewMock(time.Date(2025, time.March, 10, 12, 0, 0, 0, time.UTC))
+	accounts := services.NewAccountService(st, "test-secret", big.NewRat(0, 1), services.StaticRates{}, clk)
+
+	// The webhook is accepted while loopback is allowed, as a host that
+	// resolved publicly at creation would be, and the dispatcher is built
+	// after it no longer is, as if the host now resolved to loopback.
+	undo := allowLoopback(t)
+	hooks := services.NewWebhookService(st, accounts, clk)
+	if _, err := hooks.Create(ctx, 1, types.WebhookRequest{URL: endpoint.URL, Events: []types.EventType{types.EventDeposit}}); err != nil {
+		t.Fatal(err)
+	}
+	undo()
+
+	backoff := webhooks.Backoff
+	webhooks.Backoff = nil
+	t.Cleanup(func() { webhooks.Backoff = backoff })
+	core, logs := observer.New(zapcore.ErrorLevel)
+	dispatcher := webhooks.NewDispatcher(hooks, zap.New(core))
+	accounts.Subscribe(dispatcher.Publish)
+	go dispatcher.Run(ctx)
+
+	account, err := accounts.Create(ctx, 1, types.CreateAccountRequest{OwnerName: "Alice", Email: "alice@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := accounts.Deposit(ctx, account.ID, 12_34, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for logs.FilterMessage("webhook delivery failed, giving up").Len() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("delivery not given up within 5s")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	entry := logs.FilterMessage("webhook delivery failed, giving up").All()[0]
+	if err, _ := entry.ContextMap()["error"].(string); !strings.Contains(err, "is not public") {
+		t.Errorf("error = %q, want the address refused", err)
+	}
+	select {
+	case <-requests:
+		t.Error("endpoint on loopback received the delivery")
+	default:
+	}
+}